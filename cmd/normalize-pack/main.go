@@ -0,0 +1,72 @@
+package main
+
+// ==============================
+// NORMALIZE PACK
+// sounds papkasidagi kliplarni bir marta tozalab, xuddi shu tuzilishdagi
+// WAV paketga yozadi: boshidagi/oxiridagi sukunat kesiladi, balandlik
+// bir xil darajaga keltiriladi. Keyin audio.sounds_dir yangi papkaga
+// o'zgartiriladi va audio.preprocess o'chirilishi mumkin.
+//   normalize-pack -out sounds-normalized
+// Qiymatlar berilmasa audio.preprocess sozlamalari, ular ham bo'sh
+// bo'lsa tavsiya etilgan qiymatlar ishlatiladi.
+// ==============================
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"pos80/internal/audio"
+	"pos80/internal/config"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+func main() {
+	settings, err := config.LoadSettings(config.SettingsPath())
+	if err != nil {
+		log.Fatalf("🔥 Konfiguratsiyani yuklab bo'lmadi: %v", err)
+	}
+	p := settings.Audio.Preprocess
+	if p.TrimDB == 0 && p.TargetDB == 0 {
+		p.TrimDB = audio.DefaultTrimDB
+		p.TargetDB = audio.DefaultTargetDB
+	}
+
+	in := flag.String("in", settings.Audio.SoundsDir, "kliplar papkasi")
+	out := flag.String("out", "", "tozalangan WAV paket papkasi (majburiy)")
+	rate := flag.Int("rate", settings.Audio.SampleRate, "chiqish chastotasi (Hz)")
+	trimDB := flag.Float64("trim-db", p.TrimDB, "shundan past sukunat kesiladi (dBFS), 0 - kesilmaydi")
+	padMs := flag.Int("pad-ms", p.TrimPadMs, "kesilgan joyda qoldiriladigan sukunat (ms)")
+	targetDB := flag.Float64("target-db", p.TargetDB, "nutq RMS darajasi (dBFS), 0 - tenglanmaydi")
+	maxGainDB := flag.Float64("max-gain-db", p.MaxGainDB, "kuchaytirish chegarasi (dB)")
+	flag.Parse()
+
+	if *out == "" {
+		flag.Usage()
+		log.Fatalf("🔥 -out papkasi ko'rsatilmagan")
+	}
+
+	preprocess := audio.Preprocess{
+		TrimDB:    *trimDB,
+		TrimPad:   time.Duration(*padMs) * time.Millisecond,
+		TargetDB:  *targetDB,
+		MaxGainDB: *maxGainDB,
+	}
+	report, err := audio.NormalizePack(*in, *out, beep.SampleRate(*rate), preprocess, func(rel string, result audio.PreprocessResult) {
+		fmt.Printf("%-40s %6v -> %6v  %6.1f dB  %+5.1f dB\n", rel,
+			result.Before.Round(time.Millisecond), result.After.Round(time.Millisecond), result.LevelDB, result.GainDB)
+	})
+	if err != nil {
+		log.Fatalf("🔥 Paket tozalanmadi: %v", err)
+	}
+	for _, rel := range report.Skipped {
+		log.Printf("⚠️ O'tkazib yuborildi (yonida .mp3 bor): %s", rel)
+	}
+	for _, failure := range report.Failed {
+		log.Printf("❌ Tozalanmadi: %s: %s", failure.File, failure.Error)
+	}
+	if len(report.Failed) > 0 {
+		log.Fatalf("🔥 %d ta klip paketga kirmadi, ularni almashtirib qayta ishga tushiring", len(report.Failed))
+	}
+}
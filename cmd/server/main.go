@@ -1,15 +1,20 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"pos80/internal/api"
 	"pos80/internal/audio"
 	"pos80/internal/config"
+	"pos80/internal/queue"
 	"runtime"
+	"strings"
 	"syscall"
 
+	"github.com/faiface/beep"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,32 +25,103 @@ func main() {
 
 	// 1. KONFIGURATSIYA YUKLASH
 	log.Printf("📋 Konfiguratsiya yuklanmoqda...")
+	settings, err := config.LoadSettings(config.SettingsPath())
+	if err != nil {
+		log.Fatalf("🔥 Konfiguratsiyani yuklab bo'lmadi: %v", err)
+	}
 
 	// 2. AUDIO SERVICE YARATISH
 	log.Printf("🎵 Audio servis yaratilmoqda...")
-	audioService := audio.NewAudioService("./sounds")
+	// Har bir zonaning o'z chiqishi (bitta zonali sozlamada - audio.sink)
+	zoneSettings := settings.Audio.ZoneList()
+	zoneSinks, err := audioZoneSinks(settings, zoneSettings)
+	if err != nil {
+		log.Fatalf("🔥 Audio chiqishini sozlab bo'lmadi: %v", err)
+	}
+
+	// Render qilingan e'lonlar (zal ekranlari va admin sahifadagi tinglash uchun)
+	renders, err := audio.NewAnnouncementStore(filepath.Join(settings.DataDir, "announcements"), settings.Audio.RenderKeep)
+	if err != nil {
+		log.Printf("⚠️ E'lonlarni render qilish o'chirildi: %v", err)
+	}
+
+	// E'lon shablonlari: noto'g'ri shablon bilan ishga tushirilmaydi
+	templates := announcementTemplates(settings)
+	if err := templates.Validate(); err != nil {
+		log.Fatalf("🔥 E'lon shablonlari noto'g'ri: %v", err)
+	}
+
+	// TTS: ovoz paketida yo'q iboralar uchun (ixtiyoriy)
+	var tts *audio.TTS
+	if settings.Audio.TTS.Command != "" {
+		tts, err = audio.NewTTS(settings.Audio.TTS.Command, settings.Audio.TTS.Args,
+			settings.Audio.TTS.CacheDir, settings.Audio.TTS.Timeout(), ttsTexts(settings))
+		if err != nil {
+			log.Printf("⚠️ TTS o'chirildi: %v", err)
+		} else {
+			log.Printf("🗣️ TTS zaxirasi: %s", settings.Audio.TTS.Command)
+		}
+	}
+
+	audioService := audio.NewAudioService(settings.Audio.SoundsDir, audio.Options{
+		SampleRate: beep.SampleRate(settings.Audio.SampleRate),
+		Gap:        settings.Audio.Gap(),
+		Crossfade:  settings.Audio.Crossfade(),
+		CacheBytes: settings.Audio.CacheBytes(),
+		Language:   settings.Audio.Language,
+		Sink:       zoneSinks[defaultZoneIndex(settings, zoneSettings)],
+		Renders:    renders,
+		LevelsPath: filepath.Join(settings.DataDir, "audio-levels.json"),
+		Templates:  templates,
+		TTS:        tts,
+		Messages:   settings.Audio.Messages,
+		Preprocess: clipPreprocess(settings),
+	})
+	for _, problem := range audioService.ValidateTemplates() {
+		log.Printf("⚠️ E'lon shabloni: %s", problem)
+	}
 
 	// Audio papka mavjudligini tekshirish
-	if _, err := os.Stat("./sounds"); os.IsNotExist(err) {
+	if _, err := os.Stat(settings.Audio.SoundsDir); os.IsNotExist(err) {
 		log.Printf("⚠️ Diqqat: 'sounds' papkasi topilmadi! Audio ishlamaydi.")
 	} else {
 		log.Printf("✅ Audio fayllar papkasi topildi")
+		audioService.Preload() // E'lon paytida diskdan o'qish pauzalari bo'lmasligi uchun
 	}
 
-	// 🎯 AUDIO QUEUE SERVICE NI YARATISH VA ISHGA TUSHIRISH
+	// 🎯 AUDIO QUEUE SERVICE NI YARATISH VA ISHGA TUSHIRISH (har bir zona uchun)
 	log.Printf("🚀 Audio Queue Service yaratilmoqda...")
-	audioQueue := audio.NewAudioQueueService(audioService, 1) // ⚠️ 1 ta worker - serial execution
-	audioQueue.Start()
+	history := audio.NewHistory(filepath.Join(settings.DataDir, "audio-history.json"), settings.Audio.HistoryKeep)
+	zones := audio.NewZones(settings.Audio.DefaultZone)
+	for i, zone := range zoneSettings {
+		// ⚠️ 1 ta worker - zona ichida serial execution
+		audioQueue := audio.NewAudioQueueService(audioService, 1, settings.Audio.DedupWindow()).WithHistory(history).WithZone(zone.Name, zoneSinks[i])
+		if err := zones.Add(audioQueue, zone.Rooms); err != nil {
+			log.Fatalf("🔥 Ovoz zonalari noto'g'ri: %v", err)
+		}
+	}
+	if err := zones.Validate(); err != nil {
+		log.Fatalf("🔥 Ovoz zonalari noto'g'ri: %v", err)
+	}
+	zones.Start()
 	log.Printf("✅ Audio Queue Service ishga tushdi")
 
+	// 🗓️ JADVAL BO'YICHA XABARLAR (tushlik, yopilish)
+	scheduler := audio.NewScheduler(zones, filepath.Join(settings.DataDir, "audio-schedules.json"))
+	scheduler.Start()
+
+	// 🕐 SMENA VA NAVBAT SERVISI
+	log.Printf("🕐 Navbat servisi yaratilmoqda...")
+	queueService := queue.NewService(settings.DataDir)
+
 	// 3. ROUTER SOZLASH
 	router := gin.New()
-	api.SetupRouter(router, audioService, audioQueue) // ⚠️ audioQueue ni ham o'tkazamiz
+	api.SetupRouter(router, settings, audioService, zones, scheduler, queueService) // ⚠️ zonalar navbatlarini ham o'tkazamiz
 
 	// ==============================
 	// GRACEFUL SHUTDOWN SOZLASH
 	// ==============================
-	setupGracefulShutdown(audioService, audioQueue)
+	setupGracefulShutdown(audioService, zones, scheduler)
 
 	// ==============================
 	// SERVERNI ISHGA TUSHIRISH
@@ -62,7 +138,103 @@ func main() {
 	}
 }
 
-func setupGracefulShutdown(audioService *audio.AudioService, audioQueue *audio.AudioQueueService) {
+// audioZoneSinks - har bir zona uchun chiqish: qurilma ishlamay qolsa, zaxiraga
+// o'tib, fonda qayta ochib ko'radi (watchdog)
+func audioZoneSinks(settings *config.Settings, zones []config.ZoneSettings) ([]audio.Sink, error) {
+	sinks := make([]audio.Sink, 0, len(zones))
+	speakers := 0
+	devices := map[string]string{}
+	for _, zone := range zones {
+		switch zone.Sink {
+		case "", audio.SinkSpeaker:
+			// speaker paketi faqat standart qurilmani ochadi, ikki zona bitta karnayga tushadi
+			speakers++
+			if speakers > 1 {
+				return nil, fmt.Errorf("speaker ni faqat bitta zona ishlatishi mumkin (%s), qavatlar uchun \"device\" sink ishlating", zone.Name)
+			}
+		case audio.SinkDevice:
+			key := strings.ToLower(strings.TrimSpace(zone.Device))
+			if other, ok := devices[key]; ok {
+				return nil, fmt.Errorf("%s va %s zonalari bitta qurilmada: %q", other, zone.Name, zone.Device)
+			}
+			devices[key] = zone.Name
+		}
+
+		primary, err := audio.NewSink(zone.Sink, zone.SinkDir, zone.Device)
+		if err != nil {
+			return nil, fmt.Errorf("%s zona: %w", zone.Name, err)
+		}
+		fallback, err := audio.NewSink(settings.Audio.FallbackSink, zone.SinkDir, "")
+		if err != nil {
+			return nil, fmt.Errorf("%s zona zaxirasi: %w", zone.Name, err)
+		}
+		sinks = append(sinks, audio.NewWatchdogSink(primary, fallback, settings.Audio.RecoveryInterval()))
+		log.Printf("🔈 Audio chiqish [%s]: %s (zaxira: %s)", zone.Name, primary.Name(), fallback.Name())
+	}
+	if devices := audio.OutputDevices(); len(devices) > 0 {
+		log.Printf("🔈 Chiqish qurilmalari: %q", devices)
+	}
+	return sinks, nil
+}
+
+// defaultZoneIndex - standart zona (AudioService chiqishi shu zonaniki)
+func defaultZoneIndex(settings *config.Settings, zones []config.ZoneSettings) int {
+	for i, zone := range zones {
+		if zone.Name == settings.Audio.DefaultZone {
+			return i
+		}
+	}
+	return 0
+}
+
+// clipPreprocess - kliplarni yuklashda tozalash sozlamalari
+func clipPreprocess(settings *config.Settings) audio.Preprocess {
+	p := settings.Audio.Preprocess
+	return audio.Preprocess{
+		TrimDB:    p.TrimDB,
+		TrimPad:   p.TrimPad(),
+		TargetDB:  p.TargetDB,
+		MaxGainDB: p.MaxGainDB,
+	}
+}
+
+// announcementTemplates - sozlamalardagi shablonlar va ularning bo'lim/shifokorlarga bog'lanishi
+func announcementTemplates(settings *config.Settings) *audio.Templates {
+	templates := &audio.Templates{
+		Named:       make(map[string][]string, len(settings.Audio.Templates)),
+		Default:     settings.Audio.DefaultTemplate,
+		Departments: map[string]string{},
+		Doctors:     map[string]string{},
+	}
+	for name, items := range settings.Audio.Templates {
+		templates.Named[name] = items
+	}
+	for _, department := range settings.Departments {
+		if department.Template != "" {
+			templates.Departments[department.Name] = department.Template
+		}
+	}
+	for _, doctor := range settings.Doctors {
+		if doctor.Template != "" {
+			templates.Doctors[doctor.ID] = doctor.Template
+		}
+	}
+	return templates
+}
+
+// ttsTexts - yozuvi yo'q shifokor va bo'lim nomlari TTS da qanday aytilishi
+func ttsTexts(settings *config.Settings) map[string]string {
+	texts := map[string]string{}
+	for _, doctor := range settings.Doctors {
+		texts[audio.DoctorToken(doctor.ID)] = doctor.Name
+	}
+	for _, name := range settings.DepartmentNames() {
+		texts[audio.DepartmentToken(name)] = name
+	}
+	return texts
+}
+
+func setupGracefulShutdown(audioService *audio.AudioService, zones *audio.Zones, scheduler *audio.Scheduler) {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
@@ -72,8 +244,12 @@ func setupGracefulShutdown(audioService *audio.AudioService, audioQueue *audio.A
 		log.Println("🛑 Graceful shutdown boshlandi...")
 		log.Println("⏳ Resurslar tozalanmoqda...")
 
+		// Jadval navbatga yangi xabar qo'ymasligi uchun birinchi to'xtatiladi
+		scheduler.Stop()
+		log.Println("✅ Xabarlar jadvali to'xtatildi")
+
 		// Audio queue ni to'xtatish
-		audioQueue.Stop()
+		zones.Stop()
 		log.Println("✅ Audio Queue to'xtatildi")
 
 		// Audio service ni yopish
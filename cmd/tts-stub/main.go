@@ -0,0 +1,93 @@
+package main
+
+// ==============================
+// TTS STUB
+// Haqiqiy TTS dasturi (espeak-ng, piper) o'rnatilmagan kompyuterda
+// TTS zaxirasini sinash uchun: matn uzunligiga mos qisqa ohang yozadi.
+//   "tts": {"command": "tts-stub", "args": ["{out}", "{text}"]}
+// {text} berilmasa, matn stdin dan o'qiladi.
+// TTS_STUB_DELAY=5s - sekin (yoki qotib qolgan) TTS dasturini sinash uchun kutish.
+// ==============================
+
+import (
+	"io"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+)
+
+const (
+	sampleRate  = beep.SampleRate(22050)
+	perChar     = 60 * time.Millisecond
+	minDuration = 300 * time.Millisecond
+	toneHz      = 440.0
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatalf("foydalanish: tts-stub <chiqish.wav> [matn]")
+	}
+
+	text := strings.Join(os.Args[2:], " ")
+	if len(os.Args) < 3 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatalf("stdin o'qilmadi: %v", err)
+		}
+		text = string(data)
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		log.Fatalf("matn bo'sh")
+	}
+
+	if value := os.Getenv("TTS_STUB_DELAY"); value != "" {
+		delay, err := time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("TTS_STUB_DELAY noto'g'ri: %v", err)
+		}
+		time.Sleep(delay)
+	}
+
+	duration := time.Duration(len([]rune(text))) * perChar
+	if duration < minDuration {
+		duration = minDuration
+	}
+
+	f, err := os.Create(os.Args[1])
+	if err != nil {
+		log.Fatalf("fayl yaratilmadi: %v", err)
+	}
+	defer f.Close()
+
+	format := beep.Format{SampleRate: sampleRate, NumChannels: 1, Precision: 2}
+	if err := wav.Encode(f, tone(sampleRate.N(duration)), format); err != nil {
+		log.Fatalf("wav yozilmadi: %v", err)
+	}
+}
+
+// tone - n namunadan iborat sinus ohang
+func tone(n int) beep.Streamer {
+	position := 0
+	return beep.StreamerFunc(func(samples [][2]float64) (int, bool) {
+		if position >= n {
+			return 0, false
+		}
+		count := 0
+		for i := range samples {
+			if position >= n {
+				break
+			}
+			value := 0.3 * math.Sin(2*math.Pi*toneHz*float64(position)/float64(sampleRate))
+			samples[i] = [2]float64{value, value}
+			position++
+			count++
+		}
+		return count, true
+	})
+}
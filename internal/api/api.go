@@ -6,16 +6,35 @@ import (
 	"pos80/internal/api/handlers"
 	"pos80/internal/audio"
 	"pos80/internal/config"
+	"pos80/internal/display"
+	"pos80/internal/queue"
+	"pos80/internal/web"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(router *gin.Engine, audioService *audio.AudioService, audioQueue *audio.AudioQueueService) {
+func SetupRouter(router *gin.Engine, settings *config.Settings, audioService *audio.AudioService, zones *audio.Zones, scheduler *audio.Scheduler, queueService *queue.Service) {
 
 	printHandler := handlers.NewPrintHandler(config.DefaultPrinterName)
 
-	// ⚠️ AudioHandler ga audioQueue ni uzatamiz (audioService emas!)
-	audioHandler := handlers.NewAudioHandlerWithQueue(audioQueue)
+	// 📺 Zal ekranlari uchun umumiy hodisalar hubi
+	displayHub := display.NewHub(display.DefaultRoomHistory)
+	displayHandler := handlers.NewDisplayHandler(displayHub)
+
+	// 📢 E'lon: audio navbat + zal ekranlari (ixtiyoriy ravishda brauzer uchun WAV)
+	announcer := handlers.NewAnnouncer(audioService, zones, displayHub, settings.Audio.DisplayAudio)
+
+	// ⚠️ Ijro faqat zonalar navbatlari orqali, audioService faqat reja va render uchun
+	audioHandler := handlers.NewAudioHandlerWithQueue(audioService, zones, announcer, settings)
+	scheduleHandler := handlers.NewScheduleHandler(scheduler)
+
+	shiftHandler := handlers.NewShiftHandler(queueService, settings, displayHub, config.DefaultPrinterName)
+	kioskHandler := handlers.NewKioskHandler(queueService, settings, displayHub, config.DefaultPrinterName)
+	doctorHandler := handlers.NewDoctorPanelHandler(queueService, announcer, displayHub, settings)
+
+	// 🖥️ Sahifalar: binary ichidagi fayllar, web_dir dagi fayllar ustun
+	pageHandler := handlers.NewPageHandler(web.Files(settings.WebDir))
+	ticketStatusHandler := handlers.NewTicketStatusHandler(queueService, pageHandler)
 
 	// ==============================
 	// GLOBAL MIDDLEWARE (tartib muhim!)
@@ -42,8 +61,72 @@ func SetupRouter(router *gin.Engine, audioService *audio.AudioService, audioQueu
 
 	// YANGI QUEUE ENDPOINTLAR
 	router.GET("/api/audio/queue/status", audioHandler.HandleQueueStatus)
-	router.POST("/api/audio/queue/clear", audioHandler.HandleClearQueue)
+	router.GET("/api/audio/zones", audioHandler.HandleListZones)
+	router.GET("/api/audio/zones/:zone", audioHandler.HandleGetZone)
+	router.GET("/api/audio/tasks", audioHandler.HandleListTasks)
+	router.GET("/api/audio/tasks/current", audioHandler.HandleCurrentTask)
+	router.GET("/api/audio/tasks/:id", audioHandler.HandleGetTask)
 	router.GET("/api/audio/health", audioHandler.HandleHealth)
+	router.POST("/api/audio/plan", audioHandler.HandlePlan)
+	router.GET("/api/audio/coverage", audioHandler.HandleCoverage)
+	router.GET("/api/audio/announcements/:file", audioHandler.HandleAnnouncementWAV)
+	router.GET("/admin/audio", pageHandler.Page("audio.html"))
+
+	// OVOZ SOZLAMALARI (API Key bilan, ish vaqtida o'zgaradi)
+	audioAdmin := router.Group("/api/audio")
+	audioAdmin.Use(handlers.APIKeyMiddleware())
+	{
+		audioAdmin.GET("/levels", audioHandler.HandleGetLevels)
+		audioAdmin.PUT("/levels", audioHandler.HandleSetLevels)
+		audioAdmin.POST("/broadcast", audioHandler.HandleBroadcast)
+		audioAdmin.GET("/history", audioHandler.HandleHistory)
+		audioAdmin.POST("/repeat-last", audioHandler.HandleRepeatLast)
+		audioAdmin.POST("/replay/:ticket_id", audioHandler.HandleReplayTicket)
+		audioAdmin.POST("/queue/clear", audioHandler.HandleClearQueue)
+		audioAdmin.DELETE("/tasks/:id", audioHandler.HandleCancelTask)
+		audioAdmin.POST("/tasks/:id/front", audioHandler.HandleMoveTaskToFront)
+		audioAdmin.POST("/cache/reload", audioHandler.HandleReloadClips) // Butun paketni qayta dekodlaydi
+		audioAdmin.POST("/preview", audioHandler.HandlePreview)          // Har safar WAV faylga yoziladi
+
+		// Jadval bo'yicha xabarlar
+		audioAdmin.GET("/schedules", scheduleHandler.HandleListSchedules)
+		audioAdmin.POST("/schedules", scheduleHandler.HandleCreateSchedule)
+		audioAdmin.PUT("/schedules/:id", scheduleHandler.HandleUpdateSchedule)
+		audioAdmin.DELETE("/schedules/:id", scheduleHandler.HandleDeleteSchedule)
+		audioAdmin.POST("/schedules/:id/run", scheduleHandler.HandleRunSchedule)
+		audioAdmin.GET("/holidays", scheduleHandler.HandleGetHolidays)
+		audioAdmin.PUT("/holidays", scheduleHandler.HandleSetHolidays)
+	}
+
+	// ZAL EKRANI (SSE, WebSocket, snapshot va tablo sahifasi)
+	router.GET("/display", pageHandler.Page("display.html"))
+	router.GET("/display/stream", displayHandler.HandleStream)
+	router.GET("/display/ws", displayHandler.HandleWebSocket)
+	router.GET("/display/snapshot", displayHandler.HandleSnapshot)
+
+	// KIOSK (API Key'siz, faqat rate limit)
+	router.GET("/kiosk", pageHandler.Page("kiosk.html"))
+	router.StaticFS("/static", pageHandler.FileSystem())
+	router.GET("/api/directory", kioskHandler.HandleDirectory)
+	router.POST("/api/kiosk/tickets", handlers.KioskGuardMiddleware(), kioskHandler.HandleIssueTicket)
+
+	// CHIPTA HOLATI (chiptadagi QR kod orqali, ommaviy)
+	router.GET("/t/:ticket_id", ticketStatusHandler.HandleTicketStatus)
+
+	// SHIFOKOR PANELI (xona PIN kodi bilan)
+	router.GET("/panel", pageHandler.Page("panel.html"))
+	router.GET("/api/rooms", doctorHandler.HandleListRooms)
+	rooms := router.Group("/api/rooms/:room")
+	rooms.Use(doctorHandler.RoomPINMiddleware())
+	{
+		rooms.GET("/queue", doctorHandler.HandleRoomQueue)
+		rooms.GET("/stream", displayHandler.HandleStream)
+		rooms.POST("/call-next", doctorHandler.HandleCallNext)
+		rooms.POST("/recall", doctorHandler.HandleRecall)
+		rooms.POST("/start", doctorHandler.HandleStart)
+		rooms.POST("/complete", doctorHandler.HandleComplete)
+		rooms.POST("/skip", doctorHandler.HandleSkip)
+	}
 
 	// ==============================
 	// PROTECTED ROUTES (API Key bilan)
@@ -53,6 +136,18 @@ func SetupRouter(router *gin.Engine, audioService *audio.AudioService, audioQueu
 	api.Use(handlers.PrintGuardMiddleware()) // Faqat bu group uchun
 	{
 		api.POST("/print-ticket", printHandler.HandlePrintTicket)
+		api.POST("/api/tickets", shiftHandler.HandleIssueTicket)
+	}
+
+	// SMENA BOSHQARUVI (API Key bilan)
+	shifts := router.Group("/api")
+	shifts.Use(handlers.APIKeyMiddleware())
+	{
+		shifts.GET("/shifts", shiftHandler.HandleListShifts)
+		shifts.POST("/shifts/open", shiftHandler.HandleOpenShift)
+		shifts.POST("/shifts/:id/close", shiftHandler.HandleCloseShift)
+		shifts.GET("/shifts/:id/summary", shiftHandler.HandleShiftSummary)
+		shifts.POST("/tickets/:id/status", shiftHandler.HandleTicketStatus)
 	}
 
 	log.Printf("🌐 API route lar belgilandi")
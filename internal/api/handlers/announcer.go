@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"log"
+	"pos80/internal/audio"
+	"pos80/internal/display"
+	"time"
+)
+
+// 📢 ANNOUNCER - ovozli e'lon va zal ekranlariga chaqiriq bitta nuqtadan
+// Announcement endpointi ham, shifokor paneli ham shundan foydalanadi
+type Announcer struct {
+	audioService *audio.AudioService
+	zones        *audio.Zones // Chaqiriq xona zonasi navbatiga qo'yiladi
+	hub          *display.Hub
+	displayAudio bool // Zal ekranlari e'lonni brauzerda ham ijro etadi (audio_url)
+}
+
+func NewAnnouncer(audioService *audio.AudioService, zones *audio.Zones, hub *display.Hub, displayAudio bool) *Announcer {
+	return &Announcer{
+		audioService: audioService,
+		zones:        zones,
+		hub:          hub,
+		displayAudio: displayAudio,
+	}
+}
+
+// Announce - e'lonni audio navbatga qo'shadi va ekranlarga chaqiriq yuboradi
+// displayAudio yoqilgan bo'lsa, e'lon fonda WAV ga render qilinadi va tayyor bo'lganda
+// ekranlarga alohida "audio" hodisasi (audio_url) yuboriladi - so'rov renderni kutmaydi.
+// Audio navbat to'la bo'lsa, ekranlarga hech narsa yuborilmaydi: chaqiriq ekranda ko'rinib
+// zalda aytilmasligi va qayta urinish "recalled" bo'lib qolmasligi uchun
+func (a *Announcer) Announce(event display.Event) (display.Event, audio.AudioTask, error) {
+	req := audio.Announcement{
+		TicketID:       event.TicketID,
+		QueueNumber:    event.QueueNumber,
+		RoomNumber:     event.RoomNumber,
+		DepartmentName: event.DepartmentName,
+		DoctorID:       event.DoctorID,
+	}
+	task, err := a.zones.AddTask(req)
+	if err != nil {
+		log.Printf("⚠️ E'lon navbatga qo'yilmadi: %s (%v)", event.QueueNumber, err)
+		return event, task, err
+	}
+
+	event = a.hub.PublishCall(event)
+	if a.displayAudio {
+		go a.renderForDisplay(req, event)
+	}
+	return event, task, err
+}
+
+// renderForDisplay - e'lonni zal ekranlari uchun render qiladi va audio_url ni yuboradi
+func (a *Announcer) renderForDisplay(req audio.Announcement, call display.Event) {
+	id, _, err := a.audioService.RenderAnnouncement(req)
+	if err != nil {
+		log.Printf("⚠️ Ekran uchun e'lon render qilinmadi: %s (%v)", call.QueueNumber, err)
+		return
+	}
+
+	event := call
+	event.Timestamp = time.Time{}
+	event.Type = display.EventAudio
+	event.AudioURL = announcementURL(id)
+	a.hub.Notify(event)
+}
+
+// announcementURL - render qilingan e'lon manzili
+func announcementURL(id string) string {
+	return "/api/audio/announcements/" + id + ".wav"
+}
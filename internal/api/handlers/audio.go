@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"pos80/internal/audio"
+	"pos80/internal/config"
+	"pos80/internal/display"
+	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -20,6 +26,23 @@ type AudioRequest struct {
 	DoctorID       string `json:"doctor_id"`
 }
 
+// PlanRequest - e'lon rejasini ijro etmasdan tekshirish
+type PlanRequest struct {
+	QueueNumber    string `json:"queue_number" binding:"required"`
+	RoomNumber     string `json:"room_number" binding:"required"`
+	DepartmentName string `json:"department_name"`
+	DoctorID       string `json:"doctor_id"`
+}
+
+func (r PlanRequest) announcement() audio.Announcement {
+	return audio.Announcement{
+		QueueNumber:    r.QueueNumber,
+		RoomNumber:     r.RoomNumber,
+		DepartmentName: r.DepartmentName,
+		DoctorID:       r.DoctorID,
+	}
+}
+
 type AudioResponse struct {
 	Status    string                 `json:"status"`
 	Message   string                 `json:"message,omitempty"`
@@ -31,21 +54,26 @@ type AudioResponse struct {
 // 🎯 PERFORMANCE METRICS
 var (
 	requestCounter uint64
-	successCounter uint64
-	failureCounter uint64
+	failureCounter uint64 // Qabul qilinmagan so'rovlar (noto'g'ri JSON, navbat to'la)
 	activeRequests int32
 	startupTime    = time.Now()
 )
 
 // 🎯 AUDIO HANDLER WITH QUEUE SUPPORT
 type AudioHandler struct {
-	audioQueue *audio.AudioQueueService
+	audioService *audio.AudioService // Reja va render uchun, ijro queue orqali
+	zones        *audio.Zones        // Har bir zonaning o'z navbati
+	settings     *config.Settings    // Ovoz paketini xonalar bo'yicha tekshirish uchun
+	announcer    *Announcer          // Navbatga qo'shish + zal ekranlariga chaqiriq
 }
 
 // ⚠️ YANGI METOD: Allaqachon yaratilgan queue ni qabul qiladi
-func NewAudioHandlerWithQueue(audioQueue *audio.AudioQueueService) *AudioHandler {
+func NewAudioHandlerWithQueue(audioService *audio.AudioService, zones *audio.Zones, announcer *Announcer, settings *config.Settings) *AudioHandler {
 	handler := &AudioHandler{
-		audioQueue: audioQueue,
+		audioService: audioService,
+		zones:        zones,
+		settings:     settings,
+		announcer:    announcer,
 	}
 
 	log.Println("🚀 Audio Handler with Queue System ready!")
@@ -86,26 +114,71 @@ func (h *AudioHandler) HandleAudioAnnouncement(c *gin.Context) {
 		return
 	}
 
-	// 🚀 QUEUE GA QO'SHISH
-	h.audioQueue.AddTask(req.QueueNumber, req.RoomNumber)
+	// 🚀 QUEUE GA QO'SHISH va 📺 zal ekranlariga xabar berish
+	event, task, err := h.announcer.Announce(display.Event{
+		TicketID:       req.TicketID,
+		QueueNumber:    req.QueueNumber,
+		RoomNumber:     req.RoomNumber,
+		DepartmentName: req.DepartmentName,
+		DoctorID:       req.DoctorID,
+	})
+	if errors.Is(err, audio.ErrQueueFull) {
+		atomic.AddUint64(&failureCounter, 1)
 
-	// 📊 Queue status
-	queueStatus := h.audioQueue.GetStatus()
+		c.JSON(http.StatusServiceUnavailable, AudioResponse{
+			Status:  "error",
+			Error:   "QUEUE_FULL",
+			Message: "Audio navbati to'la, e'lon qo'shilmadi",
+			Data: map[string]interface{}{
+				"task_id": task.ID,
+			},
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+	if err != nil {
+		atomic.AddUint64(&failureCounter, 1)
+
+		c.JSON(http.StatusBadRequest, AudioResponse{
+			Status:    "error",
+			Error:     "VALIDATION_ERROR",
+			Message:   err.Error(),
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	// ⏳ wait=true - ijro tugashini kutish
+	status, message := "success", "Audio navbatga qo'shildi"
+	if wait, _ := strconv.ParseBool(c.Query("wait")); wait {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), maxAnnouncementWait)
+		task, err = h.zones.Wait(ctx, task.ID)
+		cancel()
+		status, message = waitOutcome(task, err)
+	}
+
+	// 📊 Queue status (chaqiriq qo'yilgan zona bo'yicha)
+	queueStatus, _ := h.zones.Status(task.Zone)
 
 	// ✅ Tezkor response
 	responseTime := time.Since(startTime)
 	c.JSON(http.StatusOK, AudioResponse{
-		Status:  "success",
-		Message: "Audio navbatga qo'shildi",
+		Status:  status,
+		Message: message,
 		Data: map[string]interface{}{
 			"request_id":       requestID,
+			"task_id":          task.ID,
+			"task":             task,
 			"ticket_id":        req.TicketID,
 			"queue_number":     req.QueueNumber,
 			"room_number":      req.RoomNumber,
 			"department_name":  req.DepartmentName,
 			"doctor_id":        req.DoctorID,
+			"zone":             task.Zone,
+			"display_event":    event.Type,
 			"response_time_ms": responseTime.Milliseconds(),
-			"queue_position":   queueStatus["queue_length"],
+			"queue_position":   h.queuePosition(task),
+			"queue_length":     queueStatus["queue_length"],
 			"active_workers":   queueStatus["worker_count"],
 			"total_requests":   atomic.LoadUint64(&requestCounter),
 		},
@@ -116,11 +189,240 @@ func (h *AudioHandler) HandleAudioAnnouncement(c *gin.Context) {
 		requestID, req.QueueNumber, queueStatus["queue_length"])
 }
 
+// queuePosition - taskning zona navbatidagi o'rni (1 - keyingi), ijro etilayotgan yoki tugagan bo'lsa 0
+func (h *AudioHandler) queuePosition(task audio.AudioTask) int {
+	queue, ok := h.zones.Queue(task.Zone)
+	if !ok {
+		return 0
+	}
+	return queue.Position(task.ID)
+}
+
+// maxAnnouncementWait - wait=true bo'lganda eng ko'p kutish vaqti
+const maxAnnouncementWait = 2 * time.Minute
+
+// waitOutcome - kutilgan task natijasi javob uchun
+func waitOutcome(task audio.AudioTask, err error) (string, string) {
+	if err != nil {
+		return "pending", "Ijro hali tugamadi: " + err.Error()
+	}
+	switch task.State {
+	case audio.TaskPlayed:
+		return "success", "E'lon ijro etildi"
+	case audio.TaskPartial:
+		return "partial", "E'lon qisman ijro etildi"
+	default:
+		return "error", "E'lon ijro etilmadi: " + task.Error
+	}
+}
+
+// 🔎 TASK HOLATI - GET /api/audio/tasks/:id
+func (h *AudioHandler) HandleGetTask(c *gin.Context) {
+	task, ok := h.zones.Task(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":    "error",
+			"message":   "Task topilmadi",
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      task,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 📜 E'LONLAR TARIXI - GET /api/audio/history?limit=50&ticket_id=...
+func (h *AudioHandler) HandleHistory(c *gin.Context) {
+	history := h.zones.History()
+	if history == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":    "error",
+			"message":   "E'lonlar tarixi o'chirilgan",
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	limit := 50
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":    "error",
+				"message":   "limit musbat son bo'lishi kerak",
+				"timestamp": time.Now().UTC().Format(time.RFC3339),
+			})
+			return
+		}
+		limit = n
+	}
+
+	entries := history.List(limit, c.Query("ticket_id"))
+	for i := range entries {
+		entries[i] = entries[i].Public()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      entries,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🔁 OXIRGISINI TAKRORLASH - POST /api/audio/repeat-last
+// Zal shovqinli bo'lganda oxirgi eshitilgan chaqiriq qayta e'lon qilinadi
+func (h *AudioHandler) HandleRepeatLast(c *gin.Context) {
+	history := h.zones.History()
+	if history == nil {
+		h.replayNotFound(c, "E'lonlar tarixi o'chirilgan")
+		return
+	}
+	entry, ok := history.Last()
+	if !ok {
+		h.replayNotFound(c, "Hali hech qanday e'lon ijro etilmagan")
+		return
+	}
+	h.replay(c, entry)
+}
+
+// 🎫 CHIPTA BO'YICHA QAYTA E'LON - POST /api/audio/replay/:ticket_id
+func (h *AudioHandler) HandleReplayTicket(c *gin.Context) {
+	history := h.zones.History()
+	if history == nil {
+		h.replayNotFound(c, "E'lonlar tarixi o'chirilgan")
+		return
+	}
+	entry, ok := history.LastForTicket(c.Param("ticket_id"))
+	if !ok {
+		h.replayNotFound(c, "Bu chipta uchun e'lon topilmadi")
+		return
+	}
+	h.replay(c, entry)
+}
+
+// replay - tarixdagi e'lonni navbatga qayta qo'yadi
+func (h *AudioHandler) replay(c *gin.Context, entry audio.HistoryEntry) {
+	task, err := h.zones.Repeat(entry.Announcement)
+	if err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, audio.ErrQueueFull) {
+			code = http.StatusServiceUnavailable
+		}
+		c.JSON(code, gin.H{
+			"status":    "error",
+			"message":   err.Error(),
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": fmt.Sprintf("%s qayta e'lon qilinadi", entry.QueueNumber),
+		"data": gin.H{
+			"task":     task,
+			"original": entry.Public(),
+		},
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func (h *AudioHandler) replayNotFound(c *gin.Context, message string) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"status":    "error",
+		"message":   message,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 📣 UMUMIY XABAR - POST /api/audio/broadcast
+// {"message_id": "yopilish_15"} yoki {"items": ["phrases/..."], "preempt": true}
+// "zone" berilmasa yoki "all" bo'lsa, barcha zonalarda aytiladi
+// Navbatdagi chaqiriqlardan oldin ijro etiladi; preempt - joriy e'lon to'xtatiladi
+// va xabardan keyin qayta aytiladi
+func (h *AudioHandler) HandleBroadcast(c *gin.Context) {
+	var req audio.Broadcast
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":    "error",
+			"message":   "Noto'g'ri JSON: " + err.Error(),
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	items, err := h.audioService.ResolveBroadcast(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":    "error",
+			"message":   err.Error(),
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	tasks, err := h.zones.Broadcast(req.Zone, items, req.Preempt)
+	if errors.Is(err, audio.ErrZoneNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":    "error",
+			"message":   err.Error(),
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":    "error",
+			"message":   err.Error(),
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Xabar navbat boshiga qo'yildi",
+		"data": gin.H{
+			"tasks": tasks, // Har bir zona uchun bittadan
+			"plan":  h.audioService.PlanBroadcast(items),
+		},
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🧪 E'LON REJASI - POST /api/audio/plan
+// Hech narsa ijro etilmaydi: qaysi kliplar o'ynashi va qaysilari yetishmasligi qaytariladi
+func (h *AudioHandler) HandlePlan(c *gin.Context) {
+	var req PlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, AudioResponse{
+			Status:    "error",
+			Error:     "INVALID_REQUEST",
+			Message:   "Noto'g'ri JSON: " + err.Error(),
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      h.audioService.Plan(req.announcement()),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
 // 🔧 REQUEST VALIDATION
 func (h *AudioHandler) validateRequest(req *AudioRequest) error {
 	if req.QueueNumber == "" {
 		return fmt.Errorf("queue_number talab qilinadi")
 	}
+	if _, err := audio.ParseQueueNumber(req.QueueNumber); err != nil {
+		return err
+	}
 	if req.RoomNumber == "" {
 		return fmt.Errorf("room_number talab qilinadi")
 	}
@@ -139,7 +441,8 @@ func (h *AudioHandler) HandlePlayAudio(c *gin.Context) {
 
 // 📊 QUEUE STATUS ENDPOINT
 func (h *AudioHandler) HandleQueueStatus(c *gin.Context) {
-	queueStatus := h.audioQueue.GetStatus()
+	queueStatus := h.zones.GetStatus()
+	stats := h.zones.Stats()
 
 	c.JSON(http.StatusOK, gin.H{
 		"status": "success",
@@ -147,8 +450,11 @@ func (h *AudioHandler) HandleQueueStatus(c *gin.Context) {
 			"queue": queueStatus,
 			"metrics": gin.H{
 				"total_requests":   atomic.LoadUint64(&requestCounter),
-				"successful_plays": atomic.LoadUint64(&successCounter),
-				"failed_plays":     atomic.LoadUint64(&failureCounter),
+				"rejected":         atomic.LoadUint64(&failureCounter),
+				"successful_plays": stats.Played,
+				"partial_plays":    stats.Partial,
+				"failed_plays":     stats.Failed,
+				"dropped":          stats.Dropped,
 				"active_requests":  atomic.LoadInt32(&activeRequests),
 			},
 		},
@@ -156,9 +462,113 @@ func (h *AudioHandler) HandleQueueStatus(c *gin.Context) {
 	})
 }
 
+// 🔊 ZONALAR - GET /api/audio/zones
+// Har bir zonaning navbati, chiqishi, qurilma holati va xonalari
+func (h *AudioHandler) HandleListZones(c *gin.Context) {
+	zones := make([]map[string]interface{}, 0, len(h.zones.Names()))
+	for _, name := range h.zones.Names() {
+		status, _ := h.zones.Status(name)
+		zones = append(zones, status)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      zones,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🔊 ZONA HOLATI - GET /api/audio/zones/:zone
+// Zona holati, hozir ijro etilayotgan va kutayotgan tasklar
+func (h *AudioHandler) HandleGetZone(c *gin.Context) {
+	queue, ok := h.zones.Queue(c.Param("zone"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":    "error",
+			"message":   audio.ErrZoneNotFound.Error(),
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+	status, _ := h.zones.Status(queue.Zone())
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data": gin.H{
+			"zone":    status,
+			"playing": queue.Current(),
+			"pending": queue.Pending(),
+		},
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 📋 NAVBATDAGI TASKLAR - GET /api/audio/tasks
+// Hozir ijro etilayotgan va kutayotgan tasklar (ijro tartibida)
+func (h *AudioHandler) HandleListTasks(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data": gin.H{
+			"playing": h.zones.Current(),
+			"pending": h.zones.Pending(),
+		},
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// ▶️ HOZIRGI TASK - GET /api/audio/tasks/current
+func (h *AudioHandler) HandleCurrentTask(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      h.zones.Current(),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// ❌ TASKNI BEKOR QILISH - DELETE /api/audio/tasks/:id
+func (h *AudioHandler) HandleCancelTask(c *gin.Context) {
+	task, ok := h.zones.Cancel(c.Param("id"))
+	if !ok {
+		h.taskNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   "Task bekor qilindi",
+		"data":      task,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// ⏫ NAVBAT BOSHIGA - POST /api/audio/tasks/:id/front
+func (h *AudioHandler) HandleMoveTaskToFront(c *gin.Context) {
+	task, ok := h.zones.MoveToFront(c.Param("id"))
+	if !ok {
+		h.taskNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   "Task navbat boshiga o'tkazildi",
+		"data":      task,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// taskNotFound - task kutayotganlar orasida yo'q (ijro boshlangan yoki tugagan)
+func (h *AudioHandler) taskNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, gin.H{
+		"status":    "error",
+		"message":   "Task navbatda topilmadi",
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
 // 🗑️ QUEUE NI TOZALASH
 func (h *AudioHandler) HandleClearQueue(c *gin.Context) {
-	clearedCount := h.audioQueue.ClearQueue()
+	clearedCount := h.zones.ClearQueue()
 
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
@@ -170,14 +580,172 @@ func (h *AudioHandler) HandleClearQueue(c *gin.Context) {
 	})
 }
 
+// 🎧 OLDINDAN TINGLASH - POST /api/audio/preview
+// E'lon ekranlar bilan bir xil render qilinadi va WAV manzili qaytariladi,
+// hech narsa navbatga qo'shilmaydi
+func (h *AudioHandler) HandlePreview(c *gin.Context) {
+	var req PlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, AudioResponse{
+			Status:    "error",
+			Error:     "INVALID_REQUEST",
+			Message:   "Noto'g'ri JSON: " + err.Error(),
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	id, plan, err := h.audioService.RenderAnnouncement(req.announcement())
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"status":    "error",
+			"message":   err.Error(),
+			"data":      gin.H{"plan": plan},
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data": gin.H{
+			"id":        id,
+			"audio_url": announcementURL(id),
+			"plan":      plan,
+		},
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🔊 RENDER QILINGAN E'LON - GET /api/audio/announcements/:file (<id>.wav)
+func (h *AudioHandler) HandleAnnouncementWAV(c *gin.Context) {
+	id := strings.TrimSuffix(c.Param("file"), ".wav")
+
+	path, err := h.audioService.RenderedPath(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":    "error",
+			"message":   "E'lon topilmadi",
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	// ID tasodifiy va fayl o'zgarmaydi - brauzer keshlashi mumkin
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Header("Content-Type", "audio/wav")
+	c.File(path)
+}
+
+// 🎚️ OVOZ SOZLAMALARI - GET /api/audio/levels
+func (h *AudioHandler) HandleGetLevels(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      h.audioService.Levels(),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🎚️ OVOZ SOZLAMALARINI O'ZGARTIRISH - PUT /api/audio/levels
+// Faqat yuborilgan maydonlar o'zgaradi, keyingi e'londan kuchga kiradi
+func (h *AudioHandler) HandleSetLevels(c *gin.Context) {
+	levels := h.audioService.Levels()
+	if err := c.ShouldBindJSON(&levels); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":    "error",
+			"message":   "Noto'g'ri JSON: " + err.Error(),
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	if err := h.audioService.SetLevels(levels); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":    "error",
+			"message":   err.Error(),
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      h.audioService.Levels(),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🗂️ OVOZ PAKETINI TEKSHIRISH - GET /api/audio/coverage?max_number=999
+// Sozlangan xonalar va 1..max_number raqamlari uchun har bir til paketida
+// yetishmayotgan va o'qib bo'lmaydigan kliplar ro'yxati
+func (h *AudioHandler) HandleCoverage(c *gin.Context) {
+	maxNumber := audio.MaxSpokenNumber
+	if raw := c.Query("max_number"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":    "error",
+				"message":   "max_number musbat son bo'lishi kerak",
+				"timestamp": time.Now().UTC().Format(time.RFC3339),
+			})
+			return
+		}
+		maxNumber = n
+	}
+
+	targets := audio.CoverageTargets{
+		Rooms:       h.settings.RoomNumbers(),
+		Departments: h.settings.DepartmentNames(),
+	}
+	for _, department := range h.settings.Departments {
+		if department.Prefix != "" {
+			targets.Prefixes = append(targets.Prefixes, department.Prefix)
+		}
+	}
+	for _, doctor := range h.settings.Doctors {
+		targets.Doctors = append(targets.Doctors, doctor.ID)
+	}
+	report := h.audioService.CheckCoverage(targets, maxNumber)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      report,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🔄 KLIPLARNI QAYTA YUKLASH - POST /api/audio/cache/reload
+// sounds papkasidagi fayllar almashtirilgandan keyin chaqiriladi
+func (h *AudioHandler) HandleReloadClips(c *gin.Context) {
+	loaded := h.audioService.ReloadClips()
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": fmt.Sprintf("%d ta klip qayta yuklandi", loaded),
+		"data": gin.H{
+			"loaded": loaded,
+			"cache":  h.audioService.CacheStats(),
+		},
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
 // 🏥 HEALTH CHECK
 func (h *AudioHandler) HandleHealth(c *gin.Context) {
-	queueStatus := h.audioQueue.GetStatus()
+	queueStatus := h.zones.GetStatus()
+
+	// Bitta zona qurilmasi ishlamasa ham holat "degraded"
+	devices := h.zones.DeviceStates()
 
 	healthStatus := "healthy"
 	if !queueStatus["is_running"].(bool) {
 		healthStatus = "degraded"
 	}
+	for _, device := range devices {
+		if device.State != audio.DeviceOK {
+			healthStatus = "degraded"
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status":    healthStatus,
@@ -185,5 +753,8 @@ func (h *AudioHandler) HandleHealth(c *gin.Context) {
 		"timestamp": time.Now().UTC().Format(time.RFC3339),
 		"uptime":    time.Since(startupTime).String(),
 		"queue":     queueStatus,
+		"cache":     h.audioService.CacheStats(),
+		"sink":      h.audioService.SinkName(),
+		"devices":   devices,
 	})
 }
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"pos80/internal/display"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// 📺 DISPLAY HANDLER - zaldagi televizor uchun "hozir qabulda" oqimi
+type DisplayHandler struct {
+	hub *display.Hub
+}
+
+func NewDisplayHandler(hub *display.Hub) *DisplayHandler {
+	return &DisplayHandler{hub: hub}
+}
+
+// 📡 SSE OQIMI - GET /display/stream?room=204
+// Ulanganda avval "snapshot", keyin har bir hodisa "update" sifatida yuboriladi
+func (h *DisplayHandler) HandleStream(c *gin.Context) {
+	room := streamRoom(c)
+	events, cancel := h.hub.Subscribe()
+	defer cancel()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("snapshot", h.hub.Snapshot(room, queryLimit(c)))
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			if matchesRoom(event, room) {
+				c.SSEvent("update", event)
+			}
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().UTC().Format(time.RFC3339))
+			return true
+		}
+	})
+}
+
+// 🔌 WEBSOCKET VARIANTI - GET /display/ws?room=204
+// Xabarlar JSON: {"type":"snapshot","data":{...}} yoki {"type":"update","data":{...}}
+func (h *DisplayHandler) HandleWebSocket(c *gin.Context) {
+	room := streamRoom(c)
+	limit := queryLimit(c)
+
+	server := websocket.Server{
+		// Origin tekshirilmaydi - ekranlar lokal tarmoqdagi istalgan manzildan ulanadi
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			events, cancel := h.hub.Subscribe()
+			defer cancel()
+
+			if err := websocket.JSON.Send(ws, gin.H{"type": "snapshot", "data": h.hub.Snapshot(room, limit)}); err != nil {
+				return
+			}
+
+			// Mijoz ulanishni yopganini bilish uchun kiruvchi xabarlarni o'qib turamiz
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var discard string
+				for websocket.Message.Receive(ws, &discard) == nil {
+				}
+			}()
+
+			for {
+				select {
+				case <-closed:
+					return
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					if !matchesRoom(event, room) {
+						continue
+					}
+					if err := websocket.JSON.Send(ws, gin.H{"type": "update", "data": event}); err != nil {
+						log.Printf("⚠️ WebSocket yozishda xato: %v", err)
+						return
+					}
+				}
+			}
+		},
+	}
+
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// 📸 SNAPSHOT - GET /display/snapshot?limit=5&room=204
+func (h *DisplayHandler) HandleSnapshot(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data": gin.H{
+			"rooms":       h.hub.Snapshot(c.Query("room"), queryLimit(c)),
+			"subscribers": h.hub.SubscriberCount(),
+		},
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// limit query parametrini o'qish (standart: 5)
+func queryLimit(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if err != nil || limit <= 0 {
+		return 5
+	}
+	return limit
+}
+
+// streamRoom - oqim filtri: PIN middleware tasdiqlagan xona yoki ?room= parametri
+func streamRoom(c *gin.Context) string {
+	if room := c.GetString("room"); room != "" {
+		return room
+	}
+	return c.Query("room")
+}
+
+func matchesRoom(event display.Event, room string) bool {
+	return room == "" || strings.EqualFold(event.RoomNumber, room)
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"pos80/internal/config"
+	"pos80/internal/display"
+	"pos80/internal/models"
+	"pos80/internal/queue"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 👨‍⚕️ DOCTOR PANEL HANDLER - shifokor ish joyi uchun xona navbati
+// Barcha endpointlar /api/rooms/:room ostida va xona PIN kodi bilan himoyalangan
+type DoctorPanelHandler struct {
+	queue     *queue.Service
+	announcer *Announcer
+	hub       *display.Hub
+	settings  *config.Settings
+}
+
+func NewDoctorPanelHandler(queueService *queue.Service, announcer *Announcer, hub *display.Hub, settings *config.Settings) *DoctorPanelHandler {
+	return &DoctorPanelHandler{
+		queue:     queueService,
+		announcer: announcer,
+		hub:       hub,
+		settings:  settings,
+	}
+}
+
+// 🔐 ROOM PIN MIDDLEWARE
+// PIN X-Room-PIN headerida yoki ?pin= parametrida keladi (EventSource header yubora olmaydi)
+// Xona uchun PIN sozlanmagan bo'lsa, panelga kirish yopiq
+// Bir IP dan ketma-ket pinMaxFailures ta xato PIN - xona paneli pinLockout ga yopiladi
+func (h *DoctorPanelHandler) RoomPINMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		room, ok := h.settings.FindRoom(c.Param("room"))
+		if !ok || room.PIN == "" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"status":  "error",
+				"message": "Bu xona uchun panel sozlanmagan",
+			})
+			c.Abort()
+			return
+		}
+
+		// Bloklangan bo'lsa, PIN to'g'ri bo'lsa ham tekshirilmaydi
+		ip := getClientIP(c)
+		attemptKey := ip + "|" + room.Number
+		if locked, wait := pinLocked(attemptKey); locked {
+			c.Header("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"status":  "error",
+				"message": "PIN ko'p marta noto'g'ri kiritildi, keyinroq urinib ko'ring",
+			})
+			c.Abort()
+			return
+		}
+
+		pin := c.GetHeader("X-Room-PIN")
+		if pin == "" {
+			pin = c.Query("pin")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(pin), []byte(room.PIN)) != 1 {
+			pinFailed(attemptKey)
+			log.Printf("🔐 Noto'g'ri PIN: xona %s (%s)", room.Number, ip)
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Noto'g'ri PIN",
+			})
+			c.Abort()
+			return
+		}
+		pinSucceeded(attemptKey)
+
+		c.Set("room", room.Number)
+		c.Next()
+	}
+}
+
+// 📋 XONALAR RO'YXATI - GET /api/rooms (PIN larsiz)
+func (h *DoctorPanelHandler) HandleListRooms(c *gin.Context) {
+	rooms := []gin.H{}
+	for _, number := range h.settings.RoomNumbers() {
+		room, ok := h.settings.FindRoom(number)
+		rooms = append(rooms, gin.H{
+			"number":        number,
+			"panel_enabled": ok && room.PIN != "",
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      rooms,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 📋 XONA NAVBATI - GET /api/rooms/:room/queue
+func (h *DoctorPanelHandler) HandleRoomQueue(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      h.queue.RoomQueue(c.GetString("room")),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 📢 KEYINGISINI CHAQIRISH - POST /api/rooms/:room/call-next
+// Oldingi bemor tugatilmagan bo'lsa, yakunlanadi (qabulda bo'lsa - qabul qilindi, aks holda - kelmadi)
+func (h *DoctorPanelHandler) HandleCallNext(c *gin.Context) {
+	ticket, finished, err := h.queue.CallNext(c.GetString("room"))
+	for _, previous := range finished {
+		eventType := display.EventSkipped
+		if previous.Status == models.StatusCompleted {
+			eventType = display.EventCompleted
+		}
+		h.hub.Publish(ticketEvent(previous, eventType))
+	}
+	if err != nil {
+		h.sendQueueError(c, err)
+		return
+	}
+
+	event, _, err := h.announcer.Announce(ticketEvent(ticket, display.EventCalled))
+	if err != nil {
+		h.sendAnnounceError(c, ticket, err)
+		return
+	}
+	h.sendTicket(c, ticket, event.Type)
+}
+
+// 🔁 QAYTA CHAQIRISH - POST /api/rooms/:room/recall
+func (h *DoctorPanelHandler) HandleRecall(c *gin.Context) {
+	ticket, err := h.queue.CurrentTicket(c.GetString("room"))
+	if err != nil {
+		h.sendQueueError(c, err)
+		return
+	}
+
+	event, _, err := h.announcer.Announce(ticketEvent(ticket, display.EventRecalled))
+	if err != nil {
+		h.sendAnnounceError(c, ticket, err)
+		return
+	}
+	h.sendTicket(c, ticket, event.Type)
+}
+
+// ▶️ QABULNI BOSHLASH - POST /api/rooms/:room/start
+func (h *DoctorPanelHandler) HandleStart(c *gin.Context) {
+	h.changeCurrent(c, models.StatusInProgress, display.EventStarted)
+}
+
+// ✅ QABULNI TUGATISH - POST /api/rooms/:room/complete
+func (h *DoctorPanelHandler) HandleComplete(c *gin.Context) {
+	h.changeCurrent(c, models.StatusCompleted, display.EventCompleted)
+}
+
+// ⏭️ KELMADI - POST /api/rooms/:room/skip
+func (h *DoctorPanelHandler) HandleSkip(c *gin.Context) {
+	h.changeCurrent(c, models.StatusMissed, display.EventSkipped)
+}
+
+// changeCurrent - xonadagi joriy bemorning holatini o'zgartiradi va ekranlarga xabar beradi
+func (h *DoctorPanelHandler) changeCurrent(c *gin.Context, status, eventType string) {
+	current, err := h.queue.CurrentTicket(c.GetString("room"))
+	if err != nil {
+		h.sendQueueError(c, err)
+		return
+	}
+
+	ticket, err := h.queue.UpdateStatus(current.ID, status)
+	if err != nil {
+		h.sendQueueError(c, err)
+		return
+	}
+
+	h.hub.Publish(ticketEvent(ticket, eventType))
+	h.sendTicket(c, ticket, eventType)
+}
+
+func (h *DoctorPanelHandler) sendTicket(c *gin.Context, ticket models.Ticket, eventType string) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data": gin.H{
+			"ticket": ticket,
+			"event":  eventType,
+		},
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// sendAnnounceError - chipta chaqirilgan, lekin e'lon navbatga qo'yilmadi (ekranga ham chiqmadi)
+// Shifokor "qayta chaqirish" bilan yana urinib ko'radi
+func (h *DoctorPanelHandler) sendAnnounceError(c *gin.Context, ticket models.Ticket, err error) {
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"status":  "error",
+		"message": "Chaqiriq e'lon qilinmadi (" + err.Error() + "), qayta chaqiring",
+		"data": gin.H{
+			"ticket": ticket,
+		},
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func (h *DoctorPanelHandler) sendQueueError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, queue.ErrRoomQueueEmpty), errors.Is(err, queue.ErrNoCurrentTicket):
+		status = http.StatusNotFound
+	case errors.Is(err, queue.ErrTicketFinalized):
+		status = http.StatusConflict
+	}
+
+	c.JSON(status, gin.H{
+		"status":    "error",
+		"message":   err.Error(),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"pos80/internal/config"
+	"pos80/internal/display"
+	"pos80/internal/printer"
+	"pos80/internal/queue"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 🎯 REQUEST STRUCTURES
+type KioskTicketRequest struct {
+	DoctorID       string `json:"doctor_id"`
+	DepartmentName string `json:"department_name"`
+	RoomNumber     string `json:"room_number"`
+}
+
+// 🏧 KIOSK HANDLER - bemorlar o'zi chipta oladigan sensorli ekran
+type KioskHandler struct {
+	queue           *queue.Service
+	settings        *config.Settings
+	hub             *display.Hub
+	printerService  *printer.PrinterService
+	ticketFormatter *printer.TicketFormatter
+}
+
+func NewKioskHandler(queueService *queue.Service, settings *config.Settings, hub *display.Hub, printerName string) *KioskHandler {
+	return &KioskHandler{
+		queue:           queueService,
+		settings:        settings,
+		hub:             hub,
+		printerService:  printer.NewPrinterService(printerName),
+		ticketFormatter: printer.NewTicketFormatter().WithStatusURL(settings.PublicBaseURL),
+	}
+}
+
+// 📒 MA'LUMOTNOMA - GET /api/directory
+// Bo'limlar (ochiq smena bormi) va shifokorlar ro'yxati
+func (h *KioskHandler) HandleDirectory(c *gin.Context) {
+	departments := []gin.H{}
+	seen := map[string]bool{}
+
+	addDepartment := func(name, prefix string) {
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+
+		shift, open := h.queue.CurrentShift(name)
+		departments = append(departments, gin.H{
+			"name":        name,
+			"prefix":      prefix,
+			"open":        open,
+			"last_number": shift.LastNumber,
+		})
+	}
+
+	for _, department := range h.settings.Departments {
+		addDepartment(department.Name, department.Prefix)
+	}
+	for _, doctor := range h.settings.Doctors {
+		addDepartment(doctor.Department, "")
+	}
+
+	doctors := make([]gin.H, 0, len(h.settings.Doctors))
+	for _, doctor := range h.settings.Doctors {
+		_, open := h.queue.CurrentShift(doctor.Department)
+		doctors = append(doctors, gin.H{
+			"id":             doctor.ID,
+			"name":           doctor.Name,
+			"specialization": doctor.Specialization,
+			"room":           doctor.Room,
+			"department":     doctor.Department,
+			"available":      open,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data": gin.H{
+			"departments": departments,
+			"doctors":     doctors,
+		},
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🎫 BIR BOSISHDA CHIPTA - POST /api/kiosk/tickets
+// Chipta beriladi va darhol chop etiladi.
+// Printer xatosi bo'lsa ham chipta bekor qilinmaydi: raqam ekranda ko'rsatiladi,
+// javobda "printed": false va printer xatosi qaytariladi.
+func (h *KioskHandler) HandleIssueTicket(c *gin.Context) {
+	var req KioskTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "Noto'g'ri JSON: "+err.Error())
+		return
+	}
+
+	department, room := req.DepartmentName, req.RoomNumber
+	if req.DoctorID != "" {
+		doctor, ok := h.settings.FindDoctor(req.DoctorID)
+		if !ok {
+			h.sendError(c, http.StatusNotFound, "DOCTOR_NOT_FOUND", "Shifokor topilmadi: "+req.DoctorID)
+			return
+		}
+		department, room = doctor.Department, doctor.Room
+	}
+	if department == "" || room == "" {
+		h.sendError(c, http.StatusBadRequest, "VALIDATION_ERROR", "doctor_id yoki department_name va room_number talab qilinadi")
+		return
+	}
+
+	ticket, err := h.queue.IssueTicket(department, req.DoctorID, room)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, queue.ErrNoOpenShift) {
+			status = http.StatusConflict
+		}
+		h.sendError(c, status, "TICKET_NOT_ISSUED", err.Error())
+		return
+	}
+
+	h.hub.Publish(ticketEvent(ticket, display.EventIssued))
+
+	data := gin.H{
+		"ticket":  ticket,
+		"printed": true,
+	}
+
+	if _, err := printShiftTicket(h.printerService, h.ticketFormatter, ticket); err != nil {
+		log.Printf("❌ Kiosk chiptasini chop etishda xato: %v", err)
+		data["printed"] = false
+		data["print_error"] = err.Error()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   "Chipta berildi",
+		"data":      data,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func (h *KioskHandler) sendError(c *gin.Context, status int, errorCode, message string) {
+	log.Printf("❌ XATO: %s - %s", errorCode, message)
+
+	c.JSON(status, gin.H{
+		"status":    "error",
+		"error":     errorCode,
+		"message":   message,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
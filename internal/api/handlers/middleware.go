@@ -126,6 +126,40 @@ func PrintGuardMiddleware() gin.HandlerFunc {
 	}
 }
 
+// APIKeyMiddleware - faqat API Key ni tekshiradi
+// Smena va chipta holatini boshqaradigan endpointlar uchun (body bo'sh bo'lishi mumkin)
+func APIKeyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("X-API-Key") != allowedAPIKey {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": "Invalid API Key",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// KioskGuardMiddleware - kiosk sahifasi uchun (API Key'siz, faqat rate limit)
+// Kiosk brauzerida maxfiy kalit saqlab bo'lmaydi, shuning uchun faqat tezlik cheklanadi
+func KioskGuardMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !rateLimitAllow(getClientIP(c)) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"status":  "error",
+				"message": "Too many requests, slow down",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // IP olish
 func getClientIP(c *gin.Context) string {
 	ip := c.ClientIP()
@@ -162,6 +196,63 @@ func rateLimitAllow(ip string) bool {
 	return true
 }
 
+// PIN kodni tanlab topishdan himoya: bir IP dan bir xona uchun
+// pinMaxFailures ta xato bo'lsa, oxirgi xatodan pinLockout o'tguncha kirish yopiq
+const (
+	pinMaxFailures = 5
+	pinLockout     = 5 * time.Minute
+)
+
+var (
+	pinFailureStore = make(map[string][]time.Time)
+	pinMu           sync.Mutex
+)
+
+// pinLocked - kalit (IP + xona) bloklanganmi; qancha kutish kerakligi bilan
+func pinLocked(key string) (bool, time.Duration) {
+	pinMu.Lock()
+	defer pinMu.Unlock()
+
+	failures := recentPINFailuresLocked(key)
+	if len(failures) < pinMaxFailures {
+		return false, 0
+	}
+	return true, time.Until(failures[len(failures)-1].Add(pinLockout))
+}
+
+// pinFailed - noto'g'ri PIN ni yozadi
+func pinFailed(key string) {
+	pinMu.Lock()
+	defer pinMu.Unlock()
+
+	pinFailureStore[key] = append(recentPINFailuresLocked(key), time.Now())
+}
+
+// pinSucceeded - to'g'ri PIN dan keyin xatolar hisobi tozalanadi
+func pinSucceeded(key string) {
+	pinMu.Lock()
+	defer pinMu.Unlock()
+
+	delete(pinFailureStore, key)
+}
+
+// recentPINFailuresLocked - oxirgi pinLockout ichidagi xatolar (eskilari o'chiriladi)
+func recentPINFailuresLocked(key string) []time.Time {
+	window := time.Now().Add(-pinLockout)
+	valid := []time.Time{}
+	for _, t := range pinFailureStore[key] {
+		if t.After(window) {
+			valid = append(valid, t)
+		}
+	}
+	if len(valid) == 0 {
+		delete(pinFailureStore, key)
+		return nil
+	}
+	pinFailureStore[key] = valid
+	return valid
+}
+
 // ==============================
 // CORS MIDDLEWARE
 // ==============================
@@ -232,7 +323,7 @@ func Cors() gin.HandlerFunc {
 			"*",                           // BARCHA domenlar (faqat development uchun!)
 		},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Requested-With", "X-API-Key"}, // X-API-Key qo'shing!
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Requested-With", "X-API-Key", "X-Room-PIN"}, // X-API-Key qo'shing!
 		ExposeHeaders:    []string{"Content-Length", "Content-Type"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"io/fs"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 🖥️ PAGE HANDLER - binary ichidagi (yoki diskdan almashtirilgan) sahifalar
+type PageHandler struct {
+	files fs.FS
+}
+
+func NewPageHandler(files fs.FS) *PageHandler {
+	return &PageHandler{files: files}
+}
+
+// Page - bitta faylni sahifa sifatida qaytaruvchi handler
+// Fayl har so'rovda o'qiladi, shuning uchun diskdagi o'zgarishlar darhol ko'rinadi
+func (h *PageHandler) Page(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		data, err := fs.ReadFile(h.files, name)
+		if err != nil {
+			log.Printf("❌ Sahifa topilmadi: %s (%v)", name, err)
+			c.String(http.StatusNotFound, "Sahifa topilmadi: "+name)
+			return
+		}
+
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = "text/html; charset=utf-8"
+		}
+		c.Header("Cache-Control", "no-cache")
+		c.Data(http.StatusOK, contentType, data)
+	}
+}
+
+// FileSystem - /static/* uchun http fayl tizimi (rasmlar, logotip, css)
+func (h *PageHandler) FileSystem() http.FileSystem {
+	return http.FS(h.files)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"pos80/internal/audio"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HolidaysRequest - bayram kunlari ro'yxati
+type HolidaysRequest struct {
+	Holidays []string `json:"holidays"`
+}
+
+// 🗓️ SCHEDULE HANDLER - takrorlanadigan xabarlar va bayram kunlari
+type ScheduleHandler struct {
+	scheduler *audio.Scheduler
+}
+
+func NewScheduleHandler(scheduler *audio.Scheduler) *ScheduleHandler {
+	return &ScheduleHandler{scheduler: scheduler}
+}
+
+// 📋 JADVALLAR - GET /api/audio/schedules
+func (h *ScheduleHandler) HandleListSchedules(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      h.scheduler.List(),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// ➕ JADVAL QO'SHISH - POST /api/audio/schedules
+// {"name": "Tushlik", "cron": "0 13 * * 1-5", "message_id": "tushlik"}
+func (h *ScheduleHandler) HandleCreateSchedule(c *gin.Context) {
+	req := audio.Schedule{Enabled: true}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "Noto'g'ri JSON: "+err.Error())
+		return
+	}
+
+	schedule, err := h.scheduler.Create(req)
+	if err != nil {
+		h.sendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   "Jadval qo'shildi",
+		"data":      schedule,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// ✏️ JADVALNI O'ZGARTIRISH - PUT /api/audio/schedules/:id
+// Faqat yuborilgan maydonlar o'zgaradi ({"enabled": false} - vaqtincha o'chirish)
+func (h *ScheduleHandler) HandleUpdateSchedule(c *gin.Context) {
+	req, ok := h.scheduler.Get(c.Param("id"))
+	if !ok {
+		h.sendError(c, http.StatusNotFound, "NOT_FOUND", audio.ErrScheduleNotFound.Error())
+		return
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "Noto'g'ri JSON: "+err.Error())
+		return
+	}
+
+	schedule, err := h.scheduler.Update(c.Param("id"), req)
+	if err != nil {
+		h.sendScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   "Jadval o'zgartirildi",
+		"data":      schedule,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🗑️ JADVALNI O'CHIRISH - DELETE /api/audio/schedules/:id
+func (h *ScheduleHandler) HandleDeleteSchedule(c *gin.Context) {
+	if !h.scheduler.Delete(c.Param("id")) {
+		h.sendError(c, http.StatusNotFound, "NOT_FOUND", audio.ErrScheduleNotFound.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   "Jadval o'chirildi",
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// ▶️ HOZIR AYTISH - POST /api/audio/schedules/:id/run
+// Xabarni sinash uchun, jadvaldagi vaqtga ta'sir qilmaydi
+func (h *ScheduleHandler) HandleRunSchedule(c *gin.Context) {
+	tasks, err := h.scheduler.RunNow(c.Param("id"))
+	if err != nil {
+		h.sendScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   "Xabar navbat boshiga qo'yildi",
+		"data":      tasks,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🎉 BAYRAM KUNLARI - GET /api/audio/holidays
+func (h *ScheduleHandler) HandleGetHolidays(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      h.scheduler.Holidays(),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🎉 BAYRAM KUNLARINI ALMASHTIRISH - PUT /api/audio/holidays
+// {"holidays": ["01-01", "03-21", "2026-03-20"]}
+func (h *ScheduleHandler) HandleSetHolidays(c *gin.Context) {
+	var req HolidaysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "Noto'g'ri JSON: "+err.Error())
+		return
+	}
+
+	holidays, err := h.scheduler.SetHolidays(req.Holidays)
+	if err != nil {
+		h.sendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      holidays,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func (h *ScheduleHandler) sendScheduleError(c *gin.Context, err error) {
+	if errors.Is(err, audio.ErrScheduleNotFound) {
+		h.sendError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+	h.sendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+}
+
+func (h *ScheduleHandler) sendError(c *gin.Context, status int, errorCode, message string) {
+	log.Printf("❌ XATO: %s - %s", errorCode, message)
+
+	c.JSON(status, gin.H{
+		"status":    "error",
+		"error":     errorCode,
+		"message":   message,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"pos80/internal/config"
+	"pos80/internal/display"
+	"pos80/internal/models"
+	"pos80/internal/printer"
+	"pos80/internal/queue"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 🎯 REQUEST STRUCTURES
+type OpenShiftRequest struct {
+	DepartmentName string `json:"department_name" binding:"required"`
+	Prefix         string `json:"prefix"`
+}
+
+type IssueTicketRequest struct {
+	DepartmentName string `json:"department_name" binding:"required"`
+	DoctorID       string `json:"doctor_id"`
+	RoomNumber     string `json:"room_number" binding:"required"`
+	Print          bool   `json:"print"`
+}
+
+type TicketStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// 🕐 SHIFT HANDLER - smenalar va smena ichidagi chiptalar
+type ShiftHandler struct {
+	queue           *queue.Service
+	settings        *config.Settings
+	hub             *display.Hub
+	printerService  *printer.PrinterService
+	ticketFormatter *printer.TicketFormatter
+}
+
+func NewShiftHandler(queueService *queue.Service, settings *config.Settings, hub *display.Hub, printerName string) *ShiftHandler {
+	return &ShiftHandler{
+		queue:           queueService,
+		settings:        settings,
+		hub:             hub,
+		printerService:  printer.NewPrinterService(printerName),
+		ticketFormatter: printer.NewTicketFormatter().WithStatusURL(settings.PublicBaseURL),
+	}
+}
+
+// 🟢 SMENA OCHISH
+func (h *ShiftHandler) HandleOpenShift(c *gin.Context) {
+	var req OpenShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "Noto'g'ri JSON: "+err.Error())
+		return
+	}
+
+	// Prefiks berilmasa, ma'lumotnomadagi bo'lim harfi ishlatiladi
+	prefix := req.Prefix
+	if department, ok := h.settings.FindDepartment(req.DepartmentName); ok && prefix == "" {
+		prefix = department.Prefix
+	}
+
+	shift, err := h.queue.OpenShift(req.DepartmentName, prefix)
+	if err != nil {
+		h.sendQueueError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   "Smena ochildi",
+		"data":      shift,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🔴 SMENA YOPISH - hisobot printerda chop etiladi
+// Chop etishda xato bo'lsa ham smena yopilgan hisoblanadi, xato javobda qaytariladi
+func (h *ShiftHandler) HandleCloseShift(c *gin.Context) {
+	summary, err := h.queue.CloseShift(c.Param("id"))
+	if err != nil {
+		h.sendQueueError(c, err)
+		return
+	}
+
+	data := gin.H{
+		"summary": summary,
+		"printed": true,
+	}
+
+	reportData := h.ticketFormatter.FormatShiftReport(summary)
+	if _, err := h.printerService.Print(reportData); err != nil {
+		log.Printf("❌ Smena hisobotini chop etishda xato: %v", err)
+		data["printed"] = false
+		data["print_error"] = err.Error()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   "Smena yopildi",
+		"data":      data,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 📋 SMENALAR RO'YXATI
+func (h *ShiftHandler) HandleListShifts(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      h.queue.ListShifts(),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 📊 SMENA HISOBOTI (chop etmasdan)
+func (h *ShiftHandler) HandleShiftSummary(c *gin.Context) {
+	summary, err := h.queue.Summary(c.Param("id"))
+	if err != nil {
+		h.sendQueueError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      summary,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🎫 CHIPTA BERISH - raqam bo'limning ochiq smenasidan olinadi
+func (h *ShiftHandler) HandleIssueTicket(c *gin.Context) {
+	var req IssueTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "Noto'g'ri JSON: "+err.Error())
+		return
+	}
+
+	ticket, err := h.queue.IssueTicket(req.DepartmentName, req.DoctorID, req.RoomNumber)
+	if err != nil {
+		h.sendQueueError(c, err)
+		return
+	}
+
+	h.hub.Publish(ticketEvent(ticket, display.EventIssued))
+
+	data := gin.H{
+		"ticket": ticket,
+	}
+
+	if req.Print {
+		bytesWritten, err := printShiftTicket(h.printerService, h.ticketFormatter, ticket)
+		data["printed"] = err == nil
+		if err != nil {
+			log.Printf("❌ Chiptani chop etishda xato: %v", err)
+			data["print_error"] = err.Error()
+		} else {
+			data["bytes"] = bytesWritten
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   "Chipta berildi",
+		"data":      data,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🔄 CHIPTA HOLATINI O'ZGARTIRISH
+func (h *ShiftHandler) HandleTicketStatus(c *gin.Context) {
+	var req TicketStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "Noto'g'ri JSON: "+err.Error())
+		return
+	}
+
+	ticket, err := h.queue.UpdateStatus(c.Param("id"), req.Status)
+	if err != nil {
+		h.sendQueueError(c, err)
+		return
+	}
+
+	// 📺 Qabul tugaganini zal ekranlariga bildirish
+	if ticket.Status == models.StatusCompleted {
+		h.hub.Publish(ticketEvent(ticket, display.EventCompleted))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      ticket,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// 🖨️ Smena chiptasini oddiy chipta formatida chop etish
+func printShiftTicket(ps *printer.PrinterService, tf *printer.TicketFormatter, ticket models.Ticket) (int, error) {
+	req := models.PrintRequest{
+		TicketID:       ticket.ID,
+		ShiftID:        ticket.ShiftID,
+		DoctorId:       ticket.DoctorID,
+		RoomNumber:     ticket.RoomNumber,
+		QueueNumber:    ticket.QueueNumber,
+		DepartmentName: ticket.DepartmentName,
+		Status:         ticket.Status,
+		CreatedAt:      ticket.CreatedAt.Format(time.RFC3339),
+	}
+	return ps.Print(tf.Format(req))
+}
+
+// 📺 Chiptadan ekran hodisasini yaratish
+func ticketEvent(ticket models.Ticket, eventType string) display.Event {
+	return display.Event{
+		Type:           eventType,
+		TicketID:       ticket.ID,
+		QueueNumber:    ticket.QueueNumber,
+		RoomNumber:     ticket.RoomNumber,
+		DepartmentName: ticket.DepartmentName,
+		DoctorID:       ticket.DoctorID,
+	}
+}
+
+// ❌ Navbat servisi xatolarini HTTP status kodlariga moslash
+func (h *ShiftHandler) sendQueueError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, queue.ErrShiftNotFound), errors.Is(err, queue.ErrTicketNotFound):
+		h.sendError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, queue.ErrShiftAlreadyOpen), errors.Is(err, queue.ErrShiftClosed),
+		errors.Is(err, queue.ErrNoOpenShift), errors.Is(err, queue.ErrTicketFinalized):
+		h.sendError(c, http.StatusConflict, "CONFLICT", err.Error())
+	default:
+		h.sendError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	}
+}
+
+func (h *ShiftHandler) sendError(c *gin.Context, status int, errorCode, message string) {
+	log.Printf("❌ XATO: %s - %s", errorCode, message)
+
+	c.JSON(status, gin.H{
+		"status":    "error",
+		"error":     errorCode,
+		"message":   message,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
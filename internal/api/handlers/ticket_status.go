@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"pos80/internal/queue"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 🎫 TICKET STATUS HANDLER - bemor chiptadagi QR orqali ochadigan ommaviy sahifa
+// Faqat shu chiptaning holati va oldindagilar soni ko'rsatiladi, boshqa bemorlar ma'lumoti yo'q
+type TicketStatusHandler struct {
+	queue *queue.Service
+	pages *PageHandler
+}
+
+func NewTicketStatusHandler(queueService *queue.Service, pages *PageHandler) *TicketStatusHandler {
+	return &TicketStatusHandler{
+		queue: queueService,
+		pages: pages,
+	}
+}
+
+// 🔎 GET /t/:ticket_id
+// Brauzer uchun HTML sahifa, ?format=json yoki Accept: application/json bo'lsa JSON
+func (h *TicketStatusHandler) HandleTicketStatus(c *gin.Context) {
+	if !wantsJSON(c) {
+		h.pages.Page("ticket.html")(c)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+
+	status, err := h.queue.TicketStatus(c.Param("ticket_id"))
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, queue.ErrTicketNotFound) {
+			code = http.StatusNotFound
+		}
+		c.JSON(code, gin.H{
+			"status":    "error",
+			"message":   err.Error(),
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"data":      status,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func wantsJSON(c *gin.Context) bool {
+	if c.Query("format") == "json" {
+		return true
+	}
+	accept := c.GetHeader("Accept")
+	return strings.Contains(accept, "application/json") && !strings.Contains(accept, "text/html")
+}
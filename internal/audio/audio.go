@@ -5,31 +5,71 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"runtime"
-	"strconv"
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/faiface/beep"
-	"github.com/faiface/beep/mp3"
-	"github.com/faiface/beep/speaker"
-	"github.com/faiface/beep/wav"
-)
-
-var (
-	speakerMu   sync.Mutex
-	speakerInit bool
 )
 
 type AudioService struct {
 	basePath string
+	options  Options
+	format   beep.Format // Barcha kliplar va speaker shu formatda
+	cache    *ClipCache
+	sink     Sink               // Tayyor e'lon qayerga chiqariladi (speaker, wav, null, memory)
+	renders  *AnnouncementStore // Brauzerlar uchun WAV nusxalar, nil - o'chirilgan
+
+	packMu   sync.RWMutex
+	manifest *Manifest // Tokenlarni fayllarga bog'laydi (ReloadClips da yangilanadi)
+	language string    // E'lon tili, bo'sh bo'lsa manifestdagi standart til
+
+	templates *Templates // E'lon ketma-ketligi (bo'lim/shifokor bo'yicha)
+	tts       *TTS       // Ovoz paketida yo'q tokenlar uchun, nil - o'chirilgan
+
+	levelsMu   sync.RWMutex
+	levels     Levels // Ovoz balandligi, signal, tungi rejim (API orqali o'zgaradi)
+	levelsPath string // Levels saqlanadigan fayl, bo'sh - saqlanmaydi
 }
 
-func NewAudioService(basePath string) *AudioService {
+func NewAudioService(basePath string, options Options) *AudioService {
+	if options.SampleRate <= 0 {
+		options.SampleRate = DefaultSampleRate
+	}
+
+	if err := options.Preprocess.Validate(); err != nil {
+		log.Printf("⚠️ Kliplarni tozalash: %v (o'chirildi)", err)
+		options.Preprocess = Preprocess{}
+	}
+
 	service := &AudioService{
 		basePath: basePath,
+		options:  options,
+		format:   outputFormat(options.SampleRate),
+	}
+	service.cache = NewClipCache(options.CacheBytes, service.decodeClip)
+	service.sink = options.Sink
+	if service.sink == nil {
+		service.sink = NewSpeakerSink()
+	}
+	service.language = options.Language
+	service.renders = options.Renders
+	service.levelsPath = options.LevelsPath
+	levels, err := loadLevels(options.LevelsPath)
+	if err != nil {
+		log.Printf("⚠️ %v (standart qiymatlar ishlatiladi)", err)
+	}
+	service.levels = levels
+	service.tts = options.TTS
+	service.templates = options.Templates
+	if service.templates == nil {
+		service.templates = &Templates{}
 	}
+	if err := service.templates.Validate(); err != nil {
+		log.Printf("⚠️ E'lon shablonlari: %v (standart shablon ishlatiladi)", err)
+		service.templates = &Templates{}
+		service.templates.Validate()
+	}
+	service.loadManifest()
 
 	if _, err := os.Stat(basePath); os.IsNotExist(err) {
 		log.Printf("⚠️ Audio papkasi topilmadi: %s", basePath)
@@ -40,232 +80,222 @@ func NewAudioService(basePath string) *AudioService {
 	return service
 }
 
-func (a *AudioService) InitSpeaker(sampleRate beep.SampleRate) error {
-	speakerMu.Lock()
-	defer speakerMu.Unlock()
-
-	if speakerInit {
-		return nil
+func (a *AudioService) PlayAudio(filename string) error {
+	buffer, err := a.clip(filename)
+	if err != nil {
+		return err
 	}
+	return a.play(buffer.Streamer(0, buffer.Len()))
+}
 
-	bufferSize := sampleRate.N(time.Second / 10)
-	if runtime.GOOS == "windows" {
-		bufferSize = sampleRate.N(time.Second / 5)
-	}
+// clip - klipni keshdan oladi (bo'lmasa diskdan dekodlanadi)
+func (a *AudioService) clip(filename string) (*beep.Buffer, error) {
+	return a.cache.Get(filepath.ToSlash(filename))
+}
 
-	err := speaker.Init(sampleRate, bufferSize)
-	if err != nil {
-		return fmt.Errorf("speaker init: %w", err)
-	}
+// Preload - sounds papkasidagi barcha kliplarni xotiraga yuklaydi
+func (a *AudioService) Preload() int {
+	return a.cache.Preload(a.basePath)
+}
 
-	speakerInit = true
-	log.Printf("🔊 Speaker initialized (SampleRate: %d)", sampleRate)
-	return nil
+// ReloadClips - manifestni qayta o'qiydi, keshni tozalab kliplarni qayta yuklaydi
+// (fayllar almashtirilganda)
+func (a *AudioService) ReloadClips() int {
+	a.loadManifest()
+	a.cache.Clear()
+	return a.cache.Preload(a.basePath)
 }
 
-func (a *AudioService) PlayAudio(filename string) error {
-	fullPath := filepath.Join(a.basePath, filename)
+// loadManifest - sounds/manifest.json ni o'qiydi
+// Xato bo'lsa, oldingi manifest (yoki standart) qoladi
+func (a *AudioService) loadManifest() {
+	manifest, err := LoadManifest(a.basePath)
 
-	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
-		return fmt.Errorf("fayl topilmadi: %s", fullPath)
-	}
+	a.packMu.Lock()
+	defer a.packMu.Unlock()
 
-	f, err := os.Open(fullPath)
 	if err != nil {
-		return fmt.Errorf("fayl ochilmadi: %w", err)
-	}
-	defer f.Close()
-
-	var streamer beep.StreamSeekCloser
-	var format beep.Format
-
-	ext := filepath.Ext(filename)
-	switch ext {
-	case ".mp3":
-		streamer, format, err = mp3.Decode(f)
-	case ".wav":
-		streamer, format, err = wav.Decode(f)
-	default:
-		return fmt.Errorf("format qo'llab-quvvatlanmaydi: %s", ext)
+		log.Printf("⚠️ %v", err)
+		if a.manifest == nil {
+			a.manifest = DefaultManifest()
+		}
+		return
 	}
+	a.manifest = manifest
+	log.Printf("🗂️ Ovoz paketlari: %v (standart: %s)", manifest.Languages(), manifest.DefaultLanguage)
+}
 
-	if err != nil {
-		return fmt.Errorf("decode xato: %w", err)
+// Manifest - joriy ovoz paketlari manifesti
+func (a *AudioService) Manifest() *Manifest {
+	a.packMu.RLock()
+	defer a.packMu.RUnlock()
+	return a.manifest
+}
+
+// CacheStats - kliplar keshi statistikasi
+func (a *AudioService) CacheStats() CacheStats {
+	return a.cache.Stats()
+}
+
+// play - tayyor oqimni sink orqali chiqaradi va tugashini kutadi
+func (a *AudioService) play(stream beep.StreamSeeker) error {
+	return a.sink.Play(stream, a.format)
+}
+
+// Levels - joriy ovoz sozlamalari (nusxa)
+func (a *AudioService) Levels() Levels {
+	a.levelsMu.RLock()
+	defer a.levelsMu.RUnlock()
+	return a.levels.Clone()
+}
+
+// SetLevels - ovoz sozlamalarini o'zgartiradi va saqlaydi
+// Keyingi e'londan boshlab kuchga kiradi, qayta ishga tushirish shart emas
+func (a *AudioService) SetLevels(levels Levels) error {
+	if err := levels.Validate(); err != nil {
+		return err
 	}
-	defer streamer.Close()
 
-	if err := a.InitSpeaker(format.SampleRate); err != nil {
+	a.levelsMu.Lock()
+	defer a.levelsMu.Unlock()
+
+	if err := saveLevels(a.levelsPath, levels); err != nil {
 		return err
 	}
+	a.levels = levels
+	log.Printf("🎚️ Ovoz sozlamalari yangilandi: volume=%.2f, chime=%q", levels.Volume, levels.Chime)
+	return nil
+}
 
-	done := make(chan bool, 1) // ⚠️ Buffered channel!
+// SinkName - joriy chiqish turi
+func (a *AudioService) SinkName() string {
+	return a.sink.Name()
+}
 
-	speaker.Play(beep.Seq(streamer, beep.Callback(func() {
-		select {
-		case done <- true:
-		default:
-		}
-	})))
-
-	// ⚠️ Timeout qo'shamiz - deadlock oldini olish
-	select {
-	case <-done:
-		return nil
-	case <-time.After(10 * time.Second):
-		speaker.Clear()
-		return fmt.Errorf("audio timeout")
+// DeviceState - chiqish qurilmasi holati (watchdog bo'lmasa doim "ok")
+func (a *AudioService) DeviceState() DeviceState {
+	return deviceState(a.sink)
+}
+
+func deviceState(sink Sink) DeviceState {
+	if watchdog, ok := sink.(*WatchdogSink); ok {
+		return watchdog.State()
 	}
+	return DeviceState{State: DeviceOK, Primary: sink.Name(), Active: sink.Name()}
 }
 
-// 🎵 Fayl formatini avtomatik aniqlash (.mp3 yoki .wav)
+// 🎵 Tokenni faylga bog'lash: manifest, keyin .mp3 / .wav qoidasi, keyin TTS
 func (a *AudioService) findAudioFile(baseName string) (string, error) {
-	// Avval .mp3 ni tekshiramiz
-	mp3Path := baseName + ".mp3"
-	fullMp3Path := filepath.Join(a.basePath, mp3Path)
-	if _, err := os.Stat(fullMp3Path); err == nil {
-		return mp3Path, nil
+	file, _, err := a.resolveToken("", baseName)
+	return file, err
+}
+
+// resolveToken - paketdagi fayl, bo'lmasa TTS orqali sintez qilingan WAV
+// Ikkinchi qiymat - fayl TTS dan olinganmi
+func (a *AudioService) resolveToken(language, token string) (string, bool, error) {
+	file, err := a.findClipIn(language, token)
+	if err == nil || a.tts == nil {
+		return file, false, err
 	}
 
-	// Keyin .wav ni tekshiramiz
-	wavPath := baseName + ".wav"
-	fullWavPath := filepath.Join(a.basePath, wavPath)
-	if _, err := os.Stat(fullWavPath); err == nil {
-		return wavPath, nil
+	ttsFile, ttsErr := a.synthesize(language, token)
+	if ttsErr != nil {
+		log.Printf("⚠️ %s: %v", token, ttsErr)
+		return "", false, err
 	}
+	return ttsFile, true, nil
+}
 
-	return "", fmt.Errorf("audio fayl topilmadi: %s (.mp3 yoki .wav)", baseName)
+// planToken - resolveToken, lekin TTS dasturi ishga tushirilmaydi (reja va dry-run uchun)
+// Yozuv yo'q bo'lsa, TTS aytishi belgilanadi; fayl faqat keshda bo'lsa qaytariladi,
+// aks holda bo'sh (reja klipni tts_pending deb belgilaydi)
+func (a *AudioService) planToken(language, token string) (string, bool, error) {
+	file, err := a.findClipIn(language, token)
+	if err == nil || a.tts == nil {
+		return file, false, err
+	}
+	language, pack := a.ttsPack(language)
+	if cached, ok := a.tts.Cached(language, a.tts.Text(pack, token)); ok {
+		return filepath.ToSlash(cached), true, nil
+	}
+	return "", true, nil
 }
 
-func (a *AudioService) PlayNumber(number int) error {
-	if number <= 0 {
-		return fmt.Errorf("noto'g'ri raqam: %d", number)
+// synthesize - token uchun TTS fayli (keshda bo'lmasa dastur ishga tushiriladi)
+func (a *AudioService) synthesize(language, token string) (string, error) {
+	if a.tts == nil {
+		return "", fmt.Errorf("TTS o'chirilgan: %s", token)
 	}
+	language, pack := a.ttsPack(language)
+	file, err := a.tts.Synthesize(language, a.tts.Text(pack, token))
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(file), nil
+}
 
-	switch {
-	case number == 10:
-		audioFile, err := a.findAudioFile("numbers/10a")
-		if err != nil {
-			return err
-		}
-		return a.PlayAudio(audioFile)
-	case number <= 9:
-		audioFile, err := a.findAudioFile(fmt.Sprintf("numbers/%d", number))
-		if err != nil {
-			return err
-		}
-		return a.PlayAudio(audioFile)
-	case number <= 19:
-		audioFile, err := a.findAudioFile("numbers/10")
-		if err != nil {
-			return err
-		}
-		if err := a.PlayAudio(audioFile); err != nil {
-			return err
-		}
+// ttsPack - TTS uchun til va uning paketi ("" - e'lon tili, u ham bo'sh bo'lsa standart)
+func (a *AudioService) ttsPack(language string) (string, *SoundPack) {
+	if language == "" {
+		language = a.language
+	}
+	manifest := a.Manifest()
+	if language == "" {
+		language = manifest.DefaultLanguage
+	}
+	return language, manifest.Packs[language]
+}
 
-		onesFile, err := a.findAudioFile(fmt.Sprintf("numbers/%d", number-10))
-		if err != nil {
-			return err
-		}
-		return a.PlayAudio(onesFile)
-	case number == 20:
-		audioFile, err := a.findAudioFile("numbers/20a")
-		if err != nil {
-			return err
-		}
-		return a.PlayAudio(audioFile)
-	case number == 30:
-		audioFile, err := a.findAudioFile("numbers/30a")
-		if err != nil {
-			return err
-		}
-		return a.PlayAudio(audioFile)
-	case number == 40:
-		audioFile, err := a.findAudioFile("numbers/40a")
-		if err != nil {
-			return err
-		}
-		return a.PlayAudio(audioFile)
-	case number == 50:
-		audioFile, err := a.findAudioFile("numbers/50a")
-		if err != nil {
-			return err
-		}
-		return a.PlayAudio(audioFile)
-	case number == 60:
-		audioFile, err := a.findAudioFile("numbers/60a")
-		if err != nil {
-			return err
-		}
-		return a.PlayAudio(audioFile)
-	case number == 70:
-		audioFile, err := a.findAudioFile("numbers/70a")
-		if err != nil {
-			return err
-		}
-		return a.PlayAudio(audioFile)
-	case number == 80:
-		audioFile, err := a.findAudioFile("numbers/80a")
-		if err != nil {
-			return err
-		}
-		return a.PlayAudio(audioFile)
-	case number == 90:
-		audioFile, err := a.findAudioFile("numbers/90a")
-		if err != nil {
-			return err
-		}
-		return a.PlayAudio(audioFile)
-	case number <= 99:
-		tens := (number / 10) * 10
-		ones := number % 10
+// findClipIn - tokenni berilgan til paketidan qidiradi ("" - e'lon tili)
+func (a *AudioService) findClipIn(language, token string) (string, error) {
+	if language == "" {
+		language = a.language
+	}
+	return a.Manifest().resolve(language, token, a.clipExists)
+}
 
-		tensFile, err := a.findAudioFile(fmt.Sprintf("numbers/%d", tens))
-		if err != nil {
-			return err
-		}
-		if err := a.PlayAudio(tensFile); err != nil {
-			return err
-		}
+// clipExists - fayl mavjudligi kliplar indeksidan (diskka faqat indeksda yo'q bo'lsa murojaat qilinadi)
+func (a *AudioService) clipExists(rel string) bool {
+	return a.cache.Exists(a.basePath, rel)
+}
 
-		if ones > 0 {
-			onesFile, err := a.findAudioFile(fmt.Sprintf("numbers/%d", ones))
-			if err != nil {
-				return err
-			}
-			return a.PlayAudio(onesFile)
-		}
-		return nil
-	case number == 100:
-		audioFile, err := a.findAudioFile("numbers/100")
-		if err != nil {
-			return err
-		}
-		return a.PlayAudio(audioFile)
-	case number <= 199:
-		// 101-199: "yuz" + raqam
-		audioFile, err := a.findAudioFile("numbers/100")
-		if err != nil {
-			return err
-		}
-		if err := a.PlayAudio(audioFile); err != nil {
-			return err
-		}
-		if number > 100 {
-			return a.PlayNumber(number - 100)
-		}
-		return nil
-	default:
-		return fmt.Errorf("100 dan katta raqamlar uchun audio fayl yo'q: %d", number)
+// hasClip - token uchun audio fayl mavjudligini tekshiradi
+// Faqat ovoz paketi tekshiriladi: TTS bo'lsa ham yozilgan kliplar afzal
+func (a *AudioService) hasClip(token string) bool {
+	_, err := a.findClipIn("", token)
+	return err == nil
+}
+
+// PlayNumber - sonni NumberClips qoidalari bo'yicha kliplardan yig'ib ijro etadi
+func (a *AudioService) PlayNumber(number int) error {
+	clips, err := NumberClips(number, a.hasClip)
+	if err != nil {
+		return err
 	}
+	return a.playTokens(clips)
 }
 
+// PlayRoomNumber - xona raqamini ijro etadi (yozib olingan fayl yoki raqamlardan yig'ilgan)
 func (a *AudioService) PlayRoomNumber(room string) error {
-	audioFile, err := a.findAudioFile(fmt.Sprintf("numbers/%s-xona", room))
+	clips, err := RoomClips(room, a.hasClip)
 	if err != nil {
 		return err
 	}
-	return a.PlayAudio(audioFile)
+	return a.playTokens(clips)
+}
+
+// playTokens - tokenlarni ketma-ket ijro etadi, birinchi xatoda to'xtaydi
+func (a *AudioService) playTokens(tokens []string) error {
+	for _, token := range tokens {
+		audioFile, err := a.findAudioFile(token)
+		if err != nil {
+			return err
+		}
+		if err := a.PlayAudio(audioFile); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (a *AudioService) PlayPhrase(phrase string) error {
@@ -276,63 +306,41 @@ func (a *AudioService) PlayPhrase(phrase string) error {
 	return a.PlayAudio(audioFile)
 }
 
-func (a *AudioService) PlayAnnouncement(queueNumber string, roomNumber string) error {
+// PlayAnnouncement - e'lonni ijro etadi va har bir bosqich natijasini qaytaradi
+// Xato - hech narsa eshitilmagan (noto'g'ri raqam, klip yo'q, sink ishlamadi)
+func (a *AudioService) PlayAnnouncement(req Announcement) ([]StepResult, error) {
+	results, _, err := a.playAnnouncement(a.sink, req)
+	return results, err
+}
+
+// playAnnouncement - e'lonni berilgan chiqishda ijro etadi (zona navbatlari uchun)
+func (a *AudioService) playAnnouncement(sink Sink, req Announcement) ([]StepResult, playRoute, error) {
 	log.Printf("\n🎵 ===== AUDIO E'LON BOSHLANDI =====")
-	log.Printf("📋 Navbat: %s, Xona: %s", queueNumber, roomNumber)
+	log.Printf("📋 Navbat: %s, Xona: %s", req.QueueNumber, req.RoomNumber)
 	startTime := time.Now()
 
-	ticketNum := extractNumberFromQueue(queueNumber)
-	log.Printf("🔢 Ajratilgan raqam: %d", ticketNum)
-
-	steps := []struct {
-		action func() error
-		name   string
-	}{
-		{func() error { return a.PlayNumber(ticketNum) }, "Navbat raqami"},
-		{func() error { return a.PlayPhrase("raqam_egasi") }, "Raqam egasi"},
-		{func() error { return a.PlayRoomNumber(roomNumber) }, "Xona raqami"},
-		{func() error { return a.PlayPhrase("honaga_kelishin") }, "Chaqiriq"},
+	plan := a.Plan(req)
+	if plan.Error != "" {
+		log.Printf("❌ E'lon bekor qilindi: %s", plan.Error)
+		return nil, playRoute{}, fmt.Errorf("%s", plan.Error)
+	}
+	log.Printf("🔢 Ajratilgan raqam: %s %d", plan.Prefix, plan.Number)
+	if !plan.Complete {
+		log.Printf("⚠️ Yetishmayotgan kliplar: %v", plan.Missing)
 	}
 
-	for _, step := range steps {
-		stepStart := time.Now()
-		if err := step.action(); err != nil {
-			log.Printf("⚠️ %s xato: %v", step.name, err)
-		} else {
-			log.Printf("✅ %s ijro etildi (%v)", step.name, time.Since(stepStart))
-		}
+	results, route, err := a.playPlan(sink, plan)
+	if err != nil {
+		log.Printf("⚠️ E'lon xato: %v", err)
 	}
 
 	log.Printf("✅ ===== AUDIO E'LON TUGADI (%v) =====\n", time.Since(startTime))
-	return nil
+	return results, route, err
 }
 
 func (a *AudioService) Close() {
-	speakerMu.Lock()
-	defer speakerMu.Unlock()
-
 	log.Println("🔇 Audio Service yopilmoqda...")
-	if speakerInit {
-		speaker.Clear()
-		log.Println("🔊 Speaker tozalandi")
+	if err := a.sink.Close(); err != nil {
+		log.Printf("⚠️ Sink yopilmadi: %v", err)
 	}
 }
-
-func extractNumberFromQueue(queueNumber string) int {
-	parts := strings.Split(queueNumber, "-")
-	if len(parts) > 1 {
-		cleanNum := strings.TrimLeft(parts[1], "0")
-		if cleanNum == "" {
-			cleanNum = "0"
-		}
-		if num, err := strconv.Atoi(cleanNum); err == nil {
-			return num
-		}
-	}
-
-	if num, err := strconv.Atoi(queueNumber); err == nil {
-		return num
-	}
-
-	return 0
-}
@@ -0,0 +1,55 @@
+package audio
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// testRate - test kliplari va chiqish chastotasi (qayta chastotalash bo'lmasligi uchun bir xil)
+const testRate = beep.SampleRate(8000)
+
+// testClipLength - har bir test klipining davomiyligi
+const testClipLength = 100 * time.Millisecond
+
+// testCallClips - "5" raqamli chiptani 3-xonaga chaqirish uchun kliplar (DefaultTemplate)
+// Har bir klip o'z balandligida: ijro natijasidan kliplar tartibini aniqlash mumkin
+var testCallClips = map[string]float64{
+	"numbers/5":               0.2,
+	"phrases/raqam_egasi":     0.3,
+	"numbers/3-xona":          0.4,
+	"phrases/honaga_kelishin": 0.5,
+}
+
+// writeTestPack - har bir token uchun doimiy balandlikdagi WAV klip yozadi
+func writeTestPack(t *testing.T, clips map[string]float64) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	format := outputFormat(testRate)
+	for token, level := range clips {
+		data := make([][2]float64, testRate.N(testClipLength))
+		for i := range data {
+			data[i] = [2]float64{level, level}
+		}
+		buffer := beep.NewBuffer(format)
+		buffer.Append(&samples{data: data})
+		if err := writePackClip(filepath.Join(dir, filepath.FromSlash(token)+".wav"), buffer, format); err != nil {
+			t.Fatalf("%s: %v", token, err)
+		}
+	}
+	return dir
+}
+
+// newTestService - test paketi uchun AudioService (kliplar ulanishi silliqlanmaydi)
+func newTestService(t *testing.T, dir string, sink Sink) *AudioService {
+	t.Helper()
+
+	options := DefaultOptions()
+	options.SampleRate = testRate
+	options.Crossfade = 0
+	options.Sink = sink
+	return NewAudioService(dir, options)
+}
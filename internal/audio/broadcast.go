@@ -0,0 +1,100 @@
+package audio
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// ==============================
+// FAVQULODDA VA UMUMIY XABARLAR
+// "Klinika 15 daqiqadan keyin yopiladi", evakuatsiya va h.k.
+// Navbatdagi chaqiriqlardan oldin ijro etiladi; preempt bo'lsa joriy
+// e'lon to'xtatiladi va xabardan keyin qaytadan aytiladi.
+// ==============================
+
+// Broadcast - umumiy xabar: tayyor tokenlar yoki sozlamalardagi xabar ID si
+type Broadcast struct {
+	MessageID string   `json:"message_id,omitempty"` // audio.messages dagi xabar
+	Items     []string `json:"items,omitempty"`      // "phrases/...", "ru:phrases/..."
+	Preempt   bool     `json:"preempt"`              // Joriy e'lonni to'xtatish
+	Zone      string   `json:"zone,omitempty"`       // Bo'sh yoki "all" - barcha zonalar
+}
+
+// Message - sozlamalardagi saqlangan xabar
+func (a *AudioService) Message(id string) ([]string, bool) {
+	items, ok := a.options.Messages[id]
+	return items, ok
+}
+
+// ResolveBroadcast - xabar elementlarini aniqlaydi va tekshiradi
+// Xabarda {number} kabi o'zgaruvchilar bo'lmaydi
+func (a *AudioService) ResolveBroadcast(b Broadcast) ([]string, error) {
+	items := b.Items
+	if b.MessageID != "" {
+		stored, ok := a.Message(b.MessageID)
+		if !ok {
+			return nil, fmt.Errorf("xabar topilmadi: %q", b.MessageID)
+		}
+		items = stored
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("xabar bo'sh: items yoki message_id kerak")
+	}
+	for _, item := range items {
+		if err := validateItem(item); err != nil {
+			return nil, err
+		}
+		if _, body := splitItem(item); strings.HasPrefix(body, "{") {
+			return nil, fmt.Errorf("xabarda o'zgaruvchi ishlatib bo'lmaydi: %q", item)
+		}
+	}
+	return items, nil
+}
+
+// PlanBroadcast - xabar rejasi: umumiy signal va tokenlar ketma-ketligi
+func (a *AudioService) PlanBroadcast(items []string) AnnouncementPlan {
+	levels := a.Levels()
+	plan := AnnouncementPlan{Template: TaskKindBroadcast, Missing: []string{}}
+
+	if levels.Chime != "" {
+		chimeStep := a.tokenStep("Signal", StepChime, levels.Chime)
+		for i := range chimeStep.Clips {
+			chimeStep.Clips[i].Gain *= levels.ChimeGain
+		}
+		plan.Steps = append(plan.Steps, chimeStep)
+	}
+	for _, item := range items {
+		if step, ok := a.templateStep(item, Announcement{}, QueueCode{}); ok {
+			plan.Steps = append(plan.Steps, step)
+		}
+	}
+
+	plan.summarize()
+	return plan
+}
+
+// PlayBroadcast - xabarni ijro etadi
+func (a *AudioService) PlayBroadcast(items []string) ([]StepResult, error) {
+	results, _, err := a.playBroadcast(a.sink, items)
+	return results, err
+}
+
+func (a *AudioService) playBroadcast(sink Sink, items []string) ([]StepResult, playRoute, error) {
+	log.Printf("\n📣 ===== UMUMIY XABAR BOSHLANDI =====")
+	startTime := time.Now()
+
+	plan := a.PlanBroadcast(items)
+	if !plan.Complete {
+		log.Printf("⚠️ Yetishmayotgan kliplar: %v", plan.Missing)
+	}
+
+	results, route, err := a.playPlan(sink, plan)
+	if err != nil {
+		log.Printf("⚠️ Xabar xato: %v", err)
+	}
+
+	log.Printf("📣 ===== UMUMIY XABAR TUGADI (%v) =====\n", time.Since(startTime))
+	return results, route, err
+}
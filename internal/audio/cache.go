@@ -0,0 +1,215 @@
+package audio
+
+import (
+	"container/list"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// ==============================
+// DEKODLANGAN KLIPLAR KESHI
+// MP3/WAV har safar diskdan o'qilmasligi uchun kliplar xotirada saqlanadi.
+// Hajm chegaralangan, chegaradan oshsa eng kam ishlatilgani chiqariladi (LRU).
+// ==============================
+
+// DefaultCacheBytes - kesh uchun standart chegara (64 MB)
+const DefaultCacheBytes = 64 << 20
+
+// CacheStats - kesh holati (health endpoint uchun)
+type CacheStats struct {
+	Entries    int       `json:"entries"`
+	Bytes      int64     `json:"bytes"`
+	MaxBytes   int64     `json:"max_bytes"`
+	Hits       uint64    `json:"hits"`
+	Misses     uint64    `json:"misses"`
+	Evictions  uint64    `json:"evictions"`
+	LoadErrors uint64    `json:"load_errors"`
+	LoadedAt   time.Time `json:"loaded_at"` // Oxirgi preload/reload vaqti
+}
+
+type cacheEntry struct {
+	file   string
+	buffer *beep.Buffer
+	bytes  int64
+}
+
+// ClipCache - fayl nomi bo'yicha dekodlangan kliplar
+type ClipCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	entries  map[string]*list.Element
+	lru      *list.List // Boshida eng oxirgi ishlatilgan
+	stats    CacheStats
+	load     func(file string) (*beep.Buffer, error)
+
+	// files - sounds papkasidagi fayllar mavjudligi: Preload to'ldiradi, qolganlari
+	// birinchi so'ralganda bir marta tekshiriladi. Har bir e'lon rejasi tokenlarni
+	// qidiradi, shuning uchun bu diskka murojaatsiz bo'lishi kerak
+	files map[string]bool
+}
+
+// NewClipCache - yangi kesh yaratadi
+// load - kesh bo'sh bo'lganda klipni dekodlaydigan funksiya
+func NewClipCache(maxBytes int64, load func(file string) (*beep.Buffer, error)) *ClipCache {
+	if maxBytes <= 0 {
+		maxBytes = DefaultCacheBytes
+	}
+	return &ClipCache{
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		load:     load,
+		files:    make(map[string]bool),
+	}
+}
+
+// Get - klipni keshdan oladi, bo'lmasa dekodlab saqlaydi
+func (c *ClipCache) Get(file string) (*beep.Buffer, error) {
+	c.mu.Lock()
+	if elem, ok := c.entries[file]; ok {
+		c.lru.MoveToFront(elem)
+		c.stats.Hits++
+		buffer := elem.Value.(*cacheEntry).buffer
+		c.mu.Unlock()
+		return buffer, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	// Dekodlash lock'siz: boshqa kliplar kutib qolmaydi
+	buffer, err := c.load(file)
+	if err != nil {
+		c.mu.Lock()
+		c.stats.LoadErrors++
+		c.mu.Unlock()
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.storeLocked(file, buffer)
+	return buffer, nil
+}
+
+// storeLocked - klipni saqlaydi va chegaradan oshgan eski kliplarni chiqaradi
+func (c *ClipCache) storeLocked(file string, buffer *beep.Buffer) {
+	if elem, ok := c.entries[file]; ok {
+		// Parallel yuklangan bo'lsa, mavjudi qoladi
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	entry := &cacheEntry{file: file, buffer: buffer, bytes: bufferBytes(buffer)}
+	if entry.bytes > c.maxBytes {
+		// Chegaradan katta klip saqlanmaydi, faqat shu safar ishlatiladi
+		return
+	}
+
+	c.entries[file] = c.lru.PushFront(entry)
+	c.stats.Bytes += entry.bytes
+
+	for c.stats.Bytes > c.maxBytes {
+		oldest := c.lru.Back()
+		if oldest == nil {
+			break
+		}
+		evicted := c.lru.Remove(oldest).(*cacheEntry)
+		delete(c.entries, evicted.file)
+		c.stats.Bytes -= evicted.bytes
+		c.stats.Evictions++
+	}
+}
+
+// Preload - basePath ostidagi barcha mp3/wav fayllarni keshga yuklaydi
+// Yuklanganlar soni qaytariladi, o'qib bo'lmagan fayllar log qilinadi
+func (c *ClipCache) Preload(basePath string) int {
+	loaded := 0
+	start := time.Now()
+
+	filepath.WalkDir(basePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".mp3" && ext != ".wav" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(basePath, path)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel) // Kalitlar findAudioFile bilan bir xil: "numbers/20.mp3"
+		c.mu.Lock()
+		c.files[rel] = true
+		c.mu.Unlock()
+		if _, err := c.Get(rel); err != nil {
+			log.Printf("⚠️ Klip yuklanmadi: %s (%v)", rel, err)
+			return nil
+		}
+		loaded++
+		return nil
+	})
+
+	c.mu.Lock()
+	c.stats.LoadedAt = time.Now()
+	bytes := c.stats.Bytes
+	c.mu.Unlock()
+
+	log.Printf("🎧 %d ta klip keshga yuklandi (%.1f MB, %v)", loaded, float64(bytes)/(1<<20), time.Since(start))
+	return loaded
+}
+
+// Clear - keshni va fayllar indeksini tozalaydi (fayllar o'zgarganda)
+func (c *ClipCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.stats.Bytes = 0
+	c.files = make(map[string]bool)
+}
+
+// Exists - fayl sounds papkasida bormi (rel - basePath ga nisbatan, "/" bilan)
+// Natija eslab qolinadi: keyin qo'shilgan yoki o'chirilgan fayllar ReloadClips dan keyin ko'rinadi
+func (c *ClipCache) Exists(basePath, rel string) bool {
+	c.mu.Lock()
+	exists, known := c.files[rel]
+	c.mu.Unlock()
+	if known {
+		return exists
+	}
+
+	_, err := os.Stat(filepath.Join(basePath, filepath.FromSlash(rel)))
+	exists = err == nil
+
+	c.mu.Lock()
+	c.files[rel] = exists
+	c.mu.Unlock()
+	return exists
+}
+
+// Stats - kesh statistikasi
+func (c *ClipCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	stats.MaxBytes = c.maxBytes
+	return stats
+}
+
+// bufferBytes - klip xotirada egallaydigan hajm
+func bufferBytes(buffer *beep.Buffer) int64 {
+	format := buffer.Format()
+	return int64(buffer.Len()) * int64(format.NumChannels) * int64(format.Precision)
+}
@@ -0,0 +1,206 @@
+package audio
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ==============================
+// OVOZ PAKETINI TEKSHIRISH
+// E'lon shablonlari bo'yicha sozlangan xonalar, shifokorlar va raqamlar
+// oralig'i uchun kerak bo'ladigan barcha tokenlar har bir til paketida
+// bor-yo'qligi va dekodlanishi tekshiriladi
+// ==============================
+
+// CoverageTargets - tekshiriladigan xonalar, shifokorlar va bo'limlar
+type CoverageTargets struct {
+	Rooms       []string `json:"rooms"`
+	Doctors     []string `json:"doctors"`     // Shifokor ID lari ({doctor} uchun)
+	Departments []string `json:"departments"` // Bo'lim nomlari ({department} uchun)
+	Prefixes    []string `json:"prefixes"`    // Navbat harflari ({prefix} uchun)
+}
+
+// ClipProblem - topilmagan yoki o'qib bo'lmagan klip
+type ClipProblem struct {
+	Token string   `json:"token"`
+	File  string   `json:"file,omitempty"`
+	Error string   `json:"error"`
+	Used  []string `json:"used_by"` // Qaysi raqam/xona/ibora uchun kerak (birinchi bir nechtasi)
+}
+
+// PackCoverage - bitta til paketi bo'yicha natija
+type PackCoverage struct {
+	Language    string        `json:"language"`
+	Checked     int           `json:"checked"`
+	Missing     []ClipProblem `json:"missing"`
+	Undecodable []ClipProblem `json:"undecodable"`
+	Unused      bool          `json:"unused,omitempty"` // Hech bir shablon bu tilni ishlatmaydi
+	OK          bool          `json:"ok"`
+}
+
+// CoverageReport - barcha paketlar bo'yicha natija
+type CoverageReport struct {
+	CoverageTargets
+	MaxNumber int            `json:"max_number"`
+	Packs     []PackCoverage `json:"packs"`
+	OK        bool           `json:"ok"`
+}
+
+// maxUsedBy - har bir muammo uchun ko'rsatiladigan misollar soni
+const maxUsedBy = 5
+
+// CheckCoverage - shablonlar, xonalar va 1..maxNumber raqamlari uchun paketlarni tekshiradi
+func (a *AudioService) CheckCoverage(targets CoverageTargets, maxNumber int) CoverageReport {
+	if maxNumber <= 0 || maxNumber > MaxSpokenNumber {
+		maxNumber = MaxSpokenNumber
+	}
+
+	manifest := a.Manifest()
+	report := CoverageReport{CoverageTargets: targets, MaxNumber: maxNumber, OK: true}
+
+	for _, language := range manifest.Languages() {
+		coverage := a.checkPack(manifest, language, targets, maxNumber)
+		if !coverage.OK {
+			report.OK = false
+		}
+		report.Packs = append(report.Packs, coverage)
+	}
+
+	return report
+}
+
+// checkPack - bitta til paketini tekshiradi
+func (a *AudioService) checkPack(manifest *Manifest, language string, targets CoverageTargets, maxNumber int) PackCoverage {
+	has := func(token string) bool {
+		_, err := manifest.Resolve(a.basePath, language, token)
+		return err == nil
+	}
+
+	// token -> qaysi raqam/xona uchun kerak
+	needed := make(map[string][]string)
+	need := func(token, usedBy string) {
+		if len(needed[token]) < maxUsedBy {
+			needed[token] = append(needed[token], usedBy)
+		}
+	}
+
+	coverage := PackCoverage{
+		Language:    language,
+		Missing:     []ClipProblem{},
+		Undecodable: []ClipProblem{},
+	}
+
+	defaultLanguage := a.language
+	if defaultLanguage == "" {
+		defaultLanguage = manifest.DefaultLanguage
+	}
+	slots := a.templates.slotLanguages(defaultLanguage)[language]
+	phrases := a.templates.literalTokens(defaultLanguage)[language]
+	if len(slots) == 0 && len(phrases) == 0 {
+		coverage.Unused = true
+		coverage.OK = true
+		return coverage
+	}
+
+	if slots[SlotNumber] {
+		for n := 1; n <= maxNumber; n++ {
+			tokens, _ := NumberClips(n, has)
+			for _, token := range tokens {
+				need(token, fmt.Sprintf("%d", n))
+			}
+		}
+	}
+	rooms := targets.Rooms
+	if !slots[SlotRoom] {
+		rooms = nil
+	}
+	for _, room := range rooms {
+		tokens, err := RoomClips(room, has)
+		if err != nil {
+			// Xona kodini o'qib bo'lmasa, uni aytib bo'lmaydi
+			coverage.Missing = append(coverage.Missing, ClipProblem{Token: room, Error: err.Error(), Used: []string{room}})
+			continue
+		}
+		for _, token := range tokens {
+			need(token, room+"-xona")
+		}
+	}
+	if slots[SlotPrefix] {
+		for _, prefix := range targets.Prefixes {
+			for _, token := range letterClips(prefix) {
+				need(token, prefix+"-...")
+			}
+		}
+	}
+	if slots[SlotDoctor] {
+		for _, doctor := range targets.Doctors {
+			need(nameToken("doctors", doctor), doctor)
+		}
+	}
+	if slots[SlotDepartment] {
+		for _, department := range targets.Departments {
+			need(nameToken("departments", department), department)
+		}
+	}
+	for _, phrase := range phrases {
+		need(phrase, phrase)
+	}
+
+	tokens := make([]string, 0, len(needed))
+	for token := range needed {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+
+	for _, token := range tokens {
+		coverage.Checked++
+		file, err := manifest.Resolve(a.basePath, language, token)
+		if err != nil {
+			coverage.Missing = append(coverage.Missing, ClipProblem{Token: token, Error: err.Error(), Used: needed[token]})
+			continue
+		}
+		if _, err := a.clip(file); err != nil {
+			coverage.Undecodable = append(coverage.Undecodable, ClipProblem{Token: token, File: file, Error: err.Error(), Used: needed[token]})
+		}
+	}
+
+	coverage.OK = len(coverage.Missing) == 0 && len(coverage.Undecodable) == 0
+	return coverage
+}
+
+// ValidateTemplates - shablonlardagi til paketlari va tayyor tokenlarni tekshiradi
+// Ishga tushishda chaqiriladi; raqam va xonalar uchun to'liq tekshiruv - CheckCoverage
+func (a *AudioService) ValidateTemplates() []string {
+	manifest := a.Manifest()
+	defaultLanguage := a.language
+	if defaultLanguage == "" {
+		defaultLanguage = manifest.DefaultLanguage
+	}
+
+	var problems []string
+	literals := a.templates.literalTokens(defaultLanguage)
+	for language := range a.templates.slotLanguages(defaultLanguage) {
+		if _, ok := literals[language]; !ok {
+			literals[language] = nil
+		}
+	}
+
+	languages := make([]string, 0, len(literals))
+	for language := range literals {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	for _, language := range languages {
+		if _, ok := manifest.Packs[language]; !ok {
+			problems = append(problems, fmt.Sprintf("til paketi topilmadi: %s", language))
+			continue
+		}
+		for _, token := range literals[language] {
+			if _, err := manifest.Resolve(a.basePath, language, token); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+	return problems
+}
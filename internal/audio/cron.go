@@ -0,0 +1,174 @@
+package audio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ==============================
+// CRON IFODASI
+// Besh maydon: daqiqa soat kun oy hafta_kuni
+//   "0 13 * * 1-5"    - ish kunlari 13:00 da
+//   "30 17 * * mon-sat" - dushanbadan shanbagacha 17:30 da
+//   "*/30 8-16 * * *" - 08:00 dan 16:30 gacha har yarim soatda
+// Hafta kuni: 0 yoki 7 - yakshanba. Kun va hafta kuni ikkalasi berilsa,
+// klassik cron kabi ulardan biri mos kelishi yetarli.
+// ==============================
+
+// cronSpec - tahlil qilingan cron ifodasi (har bir maydon bit niqobi)
+type cronSpec struct {
+	minute, hour, day, month, weekday uint64
+	anyDay, anyWeekday                bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var cronFields = []cronField{
+	{name: "daqiqa", min: 0, max: 59},
+	{name: "soat", min: 0, max: 23},
+	{name: "kun", min: 1, max: 31},
+	{name: "oy", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{name: "hafta kuni", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+// parseCron - "daqiqa soat kun oy hafta_kuni" ifodasini tahlil qiladi
+func parseCron(expr string) (cronSpec, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return cronSpec{}, fmt.Errorf("cron 5 maydondan iborat bo'lishi kerak (daqiqa soat kun oy hafta_kuni): %q", expr)
+	}
+
+	masks := make([]uint64, len(parts))
+	for i, part := range parts {
+		mask, err := cronFields[i].parse(part)
+		if err != nil {
+			return cronSpec{}, fmt.Errorf("cron %q: %w", expr, err)
+		}
+		masks[i] = mask
+	}
+	// 7 ham yakshanba
+	if masks[4]&(1<<7) != 0 {
+		masks[4] = masks[4]&^(1<<7) | 1
+	}
+
+	return cronSpec{
+		minute:     masks[0],
+		hour:       masks[1],
+		day:        masks[2],
+		month:      masks[3],
+		weekday:    masks[4],
+		anyDay:     parts[2] == "*",
+		anyWeekday: parts[4] == "*",
+	}, nil
+}
+
+// parse - bitta maydon: "*", "*/15", "5", "1-5", "1-5/2", "mon-fri", "1,3,5"
+func (f cronField) parse(value string) (uint64, error) {
+	var mask uint64
+	for _, item := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: noto'g'ri qadam %q", f.name, item)
+			}
+			step = n
+		}
+
+		low, high := f.min, f.max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = f.value(from); err != nil {
+				return 0, err
+			}
+			high = low
+			if isRange {
+				if high, err = f.value(to); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				high = f.max
+			}
+			if low > high {
+				return 0, fmt.Errorf("%s: oraliq teskari %q", f.name, item)
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s %d..%d oralig'ida bo'lishi kerak: %q", f.name, f.min, f.max, s)
+	}
+	return v, nil
+}
+
+// matches - vaqt (daqiqa aniqligida) ifodaga mos keladimi
+func (c cronSpec) matches(t time.Time) bool {
+	return c.dayMatches(t) && has(c.hour, t.Hour()) && has(c.minute, t.Minute())
+}
+
+func (c cronSpec) dayMatches(t time.Time) bool {
+	if !has(c.month, int(t.Month())) {
+		return false
+	}
+	day := has(c.day, t.Day())
+	weekday := has(c.weekday, int(t.Weekday()))
+	switch {
+	case c.anyDay && c.anyWeekday:
+		return true
+	case c.anyDay:
+		return weekday
+	case c.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// next - after dan keyingi birinchi mos daqiqa (bir yil ichida topilmasa false)
+func (c cronSpec) next(after time.Time) (time.Time, bool) {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(1, 0, 1)
+	for t.Before(limit) {
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !has(c.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if has(c.minute, t.Minute()) {
+			return t, true
+		}
+		t = t.Add(time.Minute)
+	}
+	return time.Time{}, false
+}
+
+func has(mask uint64, v int) bool {
+	return mask&(1<<uint(v)) != 0
+}
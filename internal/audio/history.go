@@ -0,0 +1,224 @@
+package audio
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ==============================
+// E'LONLAR TARIXI
+// Tugagan har bir task natijasi bilan yoziladi va data papkasida saqlanadi:
+// "oxirgisini takrorlash", chipta bo'yicha qayta e'lon va shikoyatlarni
+// tekshirish uchun. Eng eskilari keep dan oshganda o'chiriladi.
+// ==============================
+
+// DefaultHistoryKeep - saqlanadigan yozuvlar soni
+const DefaultHistoryKeep = 1000
+
+// HistoryEntry - bitta e'lon natijasi
+type HistoryEntry struct {
+	TaskID string `json:"task_id"`
+	Announcement
+	Kind       string       `json:"kind"`                  // call, repeat, broadcast, schedule
+	Items      []string     `json:"items,omitempty"`       // Umumiy xabar tokenlari
+	ScheduleID string       `json:"schedule_id,omitempty"` // Jadval bo'yicha xabar
+	Zone       string       `json:"zone,omitempty"`
+	State      string       `json:"state"` // played, partial, failed, dropped, cancelled
+	Error      string       `json:"error,omitempty"`
+	Steps      []StepResult `json:"steps,omitempty"`
+	Sink       string       `json:"sink,omitempty"`     // Qaysi chiqishda aytildi (speaker, wav, null)
+	Fallback   bool         `json:"fallback,omitempty"` // Asosiy qurilma o'rniga zaxirada
+	QueuedAt   time.Time    `json:"queued_at"`
+	StartedAt  *time.Time   `json:"started_at,omitempty"`
+	FinishedAt time.Time    `json:"finished_at"`
+}
+
+// Public - API javobi uchun nusxa, chipta IDsisiz
+// Chipta IDsi /t/:ticket_id sahifasining yagona kaliti, faylda saqlanadi, lekin tashqariga chiqmaydi
+func (e HistoryEntry) Public() HistoryEntry {
+	e.TicketID = ""
+	return e
+}
+
+// Heard - e'lon zalda (hech bo'lmasa qisman) eshitildimi
+func (e HistoryEntry) Heard() bool {
+	return e.State == TaskPlayed || e.State == TaskPartial
+}
+
+// History - e'lonlar tarixi (eng eskisi birinchi)
+// Faylga fonda yoziladi: Add navbat qulfi ostida chaqiriladi va disk
+// sekin bo'lsa ham navbatni ushlab turmasligi kerak
+type History struct {
+	mu      sync.Mutex
+	path    string
+	keep    int
+	entries []HistoryEntry
+
+	dirty     chan struct{} // Yozilmagan o'zgarish bor (bir nechta Add bitta yozuvga birlashadi)
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewHistory - tarixni fayldan yuklaydi; fayl bo'lmasa bo'sh tarix
+// path bo'sh bo'lsa, tarix faqat xotirada saqlanadi
+func NewHistory(path string, keep int) *History {
+	if keep <= 0 {
+		keep = DefaultHistoryKeep
+	}
+	h := &History{
+		path:  path,
+		keep:  keep,
+		dirty: make(chan struct{}, 1),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	if err := h.load(); err != nil {
+		log.Printf("⚠️ E'lonlar tarixi: %v (bo'sh tarix bilan boshlanadi)", err)
+	}
+	if path == "" {
+		close(h.done)
+	} else {
+		go h.writer()
+	}
+	return h
+}
+
+// Add - yangi yozuv qo'shadi; faylga fonda saqlanadi (kutmaydi)
+func (h *History) Add(entry HistoryEntry) {
+	h.mu.Lock()
+	h.entries = append(h.entries, entry)
+	if over := len(h.entries) - h.keep; over > 0 {
+		h.entries = append([]HistoryEntry(nil), h.entries[over:]...)
+	}
+	h.mu.Unlock()
+
+	select {
+	case h.dirty <- struct{}{}:
+	default: // Yozish allaqachon kutilmoqda, yangi yozuv ham unga kiradi
+	}
+}
+
+// Close - yozilmagan yozuvlarni faylga saqlaydi va fondagi yozuvchini to'xtatadi
+// Close dan keyin qo'shilgan yozuvlar faqat xotirada qoladi
+func (h *History) Close() {
+	h.closeOnce.Do(func() {
+		close(h.stop)
+	})
+	<-h.done
+}
+
+// List - oxirgi yozuvlar, eng yangisi birinchi
+// ticketID bo'sh bo'lmasa, faqat shu chipta yozuvlari
+func (h *History) List(limit int, ticketID string) []HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	result := []HistoryEntry{}
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+		if ticketID != "" && h.entries[i].TicketID != ticketID {
+			continue
+		}
+		result = append(result, h.entries[i])
+	}
+	return result
+}
+
+// Last - oxirgi eshitilgan chaqiriq (takrorlashlar ham hisobga olinadi)
+func (h *History) Last() (HistoryEntry, bool) {
+	return h.find(func(entry HistoryEntry) bool {
+		return entry.Heard() && (entry.Kind == TaskKindCall || entry.Kind == TaskKindRepeat)
+	})
+}
+
+// LastForTicket - chiptaning oxirgi chaqirig'i (eshitilmagan bo'lsa ham)
+func (h *History) LastForTicket(ticketID string) (HistoryEntry, bool) {
+	return h.find(func(entry HistoryEntry) bool {
+		return entry.TicketID == ticketID && entry.Kind != TaskKindBroadcast
+	})
+}
+
+func (h *History) find(match func(HistoryEntry) bool) (HistoryEntry, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		if match(h.entries[i]) {
+			return h.entries[i], true
+		}
+	}
+	return HistoryEntry{}, false
+}
+
+func (h *History) load() error {
+	if h.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(h.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fayl o'qilmadi: %w", err)
+	}
+	if err := json.Unmarshal(data, &h.entries); err != nil {
+		h.entries = nil
+		return fmt.Errorf("fayl buzilgan: %w", err)
+	}
+	if over := len(h.entries) - h.keep; over > 0 {
+		h.entries = h.entries[over:]
+	}
+	return nil
+}
+
+// writer - o'zgarishlarni faylga yozadi; to'xtatilganda oxirgi o'zgarishni saqlaydi
+func (h *History) writer() {
+	defer close(h.done)
+	for {
+		select {
+		case <-h.dirty:
+			h.save()
+		case <-h.stop:
+			select {
+			case <-h.dirty:
+				h.save()
+			default:
+			}
+			return
+		}
+	}
+}
+
+// save - yozuvlar nusxasini vaqtinchalik faylga yozib, keyin almashtiradi
+// Faqat writer chaqiradi; fayl yozilayotganda mu ushlanmaydi
+func (h *History) save() {
+	h.mu.Lock()
+	entries := append([]HistoryEntry(nil), h.entries...)
+	h.mu.Unlock()
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		log.Printf("❌ E'lonlar tarixini serializatsiya qilib bo'lmadi: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		log.Printf("❌ Data papkasini yaratib bo'lmadi: %v", err)
+		return
+	}
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		log.Printf("❌ E'lonlar tarixini yozib bo'lmadi: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		log.Printf("❌ E'lonlar tarixini almashtirib bo'lmadi: %v", err)
+	}
+}
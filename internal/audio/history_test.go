@@ -0,0 +1,34 @@
+package audio
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistoryCloseSavesEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audio-history.json")
+
+	history := NewHistory(path, 3)
+	for _, number := range []string{"A001", "A002", "A003", "A004"} {
+		history.Add(HistoryEntry{
+			TaskID:       number,
+			Announcement: Announcement{QueueNumber: number},
+			Kind:         TaskKindCall,
+			State:        TaskPlayed,
+			FinishedAt:   time.Now(),
+		})
+	}
+	history.Close()
+
+	loaded := NewHistory(path, 3)
+	defer loaded.Close()
+
+	entries := loaded.List(0, "")
+	if len(entries) != 3 {
+		t.Fatalf("yozuvlar soni = %d, kutilgan 3", len(entries))
+	}
+	if entries[0].QueueNumber != "A004" || entries[2].QueueNumber != "A002" {
+		t.Fatalf("yozuvlar tartibi noto'g'ri: %s ... %s", entries[0].QueueNumber, entries[2].QueueNumber)
+	}
+}
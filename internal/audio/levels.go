@@ -0,0 +1,215 @@
+package audio
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/effects"
+)
+
+// ==============================
+// OVOZ BALANDLIGI, SIGNAL VA TUNGI REJIM
+// Ish vaqtida API orqali o'zgartiriladi va data papkasida saqlanadi,
+// serverni qayta ishga tushirish shart emas
+// ==============================
+
+// MaxGain - ruxsat etilgan eng katta kuchaytirish (4x ~ +12 dB)
+const MaxGain = 4.0
+
+// QuietHours - tungi (sokin) vaqt oralig'i, masalan 20:00 - 07:00
+type QuietHours struct {
+	Start  string  `json:"start"`  // "HH:MM"
+	End    string  `json:"end"`    // "HH:MM", Start dan kichik bo'lsa yarim tundan o'tadi
+	Volume float64 `json:"volume"` // Shu oraliqda umumiy ovoz o'rniga ishlatiladi
+}
+
+// Levels - ovoz sozlamalari
+type Levels struct {
+	Volume           float64            `json:"volume"`            // Umumiy ovoz: 1.0 - yozilganidek
+	Chime            string             `json:"chime"`             // E'londan oldingi signal tokeni ("notification"), bo'sh - signalsiz
+	ChimeGain        float64            `json:"chime_gain"`        // Signal balandligi
+	DepartmentChimes map[string]string  `json:"department_chimes"` // Bo'lim -> signal ("" - shu bo'lim uchun signalsiz)
+	ClipGain         map[string]float64 `json:"clip_gain"`         // Token -> kuchaytirish (past yozilgan kliplar uchun)
+	QuietHours       *QuietHours        `json:"quiet_hours,omitempty"`
+}
+
+// DefaultLevels - signalsiz, yozilgan ovoz balandligida
+func DefaultLevels() Levels {
+	return Levels{
+		Volume:           1,
+		ChimeGain:        1,
+		DepartmentChimes: map[string]string{},
+		ClipGain:         map[string]float64{},
+	}
+}
+
+// Validate - qiymatlarni tekshiradi va bo'sh xaritalarni to'ldiradi
+func (l *Levels) Validate() error {
+	if err := validGain("volume", l.Volume); err != nil {
+		return err
+	}
+	if err := validGain("chime_gain", l.ChimeGain); err != nil {
+		return err
+	}
+	for token, gain := range l.ClipGain {
+		if err := validGain("clip_gain["+token+"]", gain); err != nil {
+			return err
+		}
+	}
+	if l.QuietHours != nil {
+		if _, err := parseClock(l.QuietHours.Start); err != nil {
+			return fmt.Errorf("quiet_hours.start: %w", err)
+		}
+		if _, err := parseClock(l.QuietHours.End); err != nil {
+			return fmt.Errorf("quiet_hours.end: %w", err)
+		}
+		if err := validGain("quiet_hours.volume", l.QuietHours.Volume); err != nil {
+			return err
+		}
+	}
+
+	if l.DepartmentChimes == nil {
+		l.DepartmentChimes = map[string]string{}
+	}
+	if l.ClipGain == nil {
+		l.ClipGain = map[string]float64{}
+	}
+	return nil
+}
+
+// Clone - xaritalari bilan birga nusxa (API o'zgartirishlari joriy qiymatga tegmasligi uchun)
+func (l Levels) Clone() Levels {
+	clone := l
+	clone.DepartmentChimes = make(map[string]string, len(l.DepartmentChimes))
+	for name, chime := range l.DepartmentChimes {
+		clone.DepartmentChimes[name] = chime
+	}
+	clone.ClipGain = make(map[string]float64, len(l.ClipGain))
+	for token, gain := range l.ClipGain {
+		clone.ClipGain[token] = gain
+	}
+	if l.QuietHours != nil {
+		quiet := *l.QuietHours
+		clone.QuietHours = &quiet
+	}
+	return clone
+}
+
+// ChimeFor - bo'lim uchun signal tokeni
+func (l Levels) ChimeFor(department string) string {
+	for name, chime := range l.DepartmentChimes {
+		if strings.EqualFold(name, department) {
+			return chime
+		}
+	}
+	return l.Chime
+}
+
+// VolumeAt - berilgan vaqtdagi umumiy ovoz (tungi rejim hisobga olinadi)
+func (l Levels) VolumeAt(now time.Time) float64 {
+	if l.QuietHours != nil && l.QuietHours.contains(now) {
+		return l.QuietHours.Volume
+	}
+	return l.Volume
+}
+
+// Gain - token uchun kuchaytirish (sozlanmagan bo'lsa 1)
+func (l Levels) Gain(token string) float64 {
+	if gain, ok := l.ClipGain[token]; ok {
+		return gain
+	}
+	return 1
+}
+
+func (q QuietHours) contains(now time.Time) bool {
+	start, err1 := parseClock(q.Start)
+	end, err2 := parseClock(q.End)
+	if err1 != nil || err2 != nil || start == end {
+		return false
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	// Yarim tundan o'tadigan oraliq: 20:00 - 07:00
+	return minute >= start || minute < end
+}
+
+// parseClock - "HH:MM" -> kun boshidan daqiqalar
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("vaqt HH:MM formatida bo'lishi kerak: %q", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func validGain(name string, gain float64) error {
+	if gain < 0 || gain > MaxGain || math.IsNaN(gain) {
+		return fmt.Errorf("%s 0 va %.0f oralig'ida bo'lishi kerak: %v", name, MaxGain, gain)
+	}
+	return nil
+}
+
+// withGain - oqimga chiziqli kuchaytirish qo'llaydi (beep/effects.Volume, 2 asosida)
+func withGain(s beep.Streamer, gain float64) beep.Streamer {
+	if gain == 1 {
+		return s
+	}
+	return &effects.Volume{
+		Streamer: s,
+		Base:     2,
+		Volume:   math.Log2(gain),
+		Silent:   gain == 0,
+	}
+}
+
+// loadLevels - saqlangan sozlamalarni o'qiydi, fayl bo'lmasa standart qiymatlar
+func loadLevels(path string) (Levels, error) {
+	levels := DefaultLevels()
+	if path == "" {
+		return levels, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return levels, nil
+	}
+	if err != nil {
+		return levels, fmt.Errorf("ovoz sozlamalari o'qilmadi: %w", err)
+	}
+	if err := json.Unmarshal(data, &levels); err != nil {
+		return DefaultLevels(), fmt.Errorf("ovoz sozlamalari noto'g'ri: %w", err)
+	}
+	if err := levels.Validate(); err != nil {
+		return DefaultLevels(), err
+	}
+	return levels, nil
+}
+
+// saveLevels - sozlamalarni vaqtinchalik faylga yozib, keyin almashtiradi
+func saveLevels(path string, levels Levels) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(levels, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("ovoz sozlamalari saqlanmadi: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("ovoz sozlamalari saqlanmadi: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,217 @@
+package audio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/mp3"
+	"github.com/faiface/beep/wav"
+)
+
+// ==============================
+// E'LONNI BITTA UZLUKSIZ OQIMGA YIG'ISH
+// Har bir klip chiqish chastotasiga keltiriladi (beep.Resample),
+// bosqichlar orasiga pauza, so'zlar orasiga qisqa crossfade qo'yiladi
+// ==============================
+
+const (
+	DefaultSampleRate = beep.SampleRate(44100)
+	DefaultGap        = 150 * time.Millisecond
+	DefaultCrossfade  = 15 * time.Millisecond
+
+	// resampleQuality - beep.Resample sifati (1-6), nutq uchun 4 yetarli
+	resampleQuality = 4
+)
+
+// Options - AudioService sozlamalari
+type Options struct {
+	SampleRate beep.SampleRate     // Chiqish chastotasi, speaker ham shu chastotada ochiladi
+	Gap        time.Duration       // Bosqichlar orasidagi sukunat
+	Crossfade  time.Duration       // Bir bosqich ichidagi kliplar ulanishi
+	CacheBytes int64               // Dekodlangan kliplar keshi chegarasi
+	Language   string              // Ovoz paketi tili, bo'sh bo'lsa manifestdagi standart
+	Sink       Sink                // Chiqish, nil bo'lsa speaker
+	Renders    *AnnouncementStore  // Zal ekranlari uchun WAV nusxalar, nil - o'chirilgan
+	LevelsPath string              // Ovoz sozlamalari fayli (API orqali o'zgartiriladi)
+	Templates  *Templates          // E'lon shablonlari, nil - DefaultTemplate
+	TTS        *TTS                // Yozuvi yo'q tokenlar uchun, nil - o'chirilgan
+	Messages   map[string][]string // Saqlangan umumiy xabarlar: ID -> tokenlar
+	Preprocess Preprocess          // Kliplarni yuklashda sukunatni kesish va balandlikni tenglash
+}
+
+// DefaultOptions - standart sozlamalar
+func DefaultOptions() Options {
+	return Options{
+		SampleRate: DefaultSampleRate,
+		Gap:        DefaultGap,
+		Crossfade:  DefaultCrossfade,
+		CacheBytes: DefaultCacheBytes,
+	}
+}
+
+// outputFormat - barcha kliplar keltiriladigan format (stereo, 16-bit)
+func outputFormat(rate beep.SampleRate) beep.Format {
+	return beep.Format{SampleRate: rate, NumChannels: 2, Precision: 2}
+}
+
+// decodeClip - faylni o'qiydi va chiqish chastotasiga keltirib xotiraga yuklaydi
+// Preprocess yoqilgan bo'lsa, sukunat kesiladi va balandlik tenglashtiriladi
+func (a *AudioService) decodeClip(filename string) (*beep.Buffer, error) {
+	// TTS fayllari sounds papkasidan tashqarida, to'liq yo'l bilan keladi
+	fullPath := filepath.FromSlash(filename)
+	if !filepath.IsAbs(fullPath) {
+		fullPath = filepath.Join(a.basePath, filename)
+	}
+
+	buffer, err := decodeFile(fullPath, a.format)
+	if err != nil {
+		return nil, err
+	}
+	if a.options.Preprocess.Enabled() {
+		buffer, _ = a.options.Preprocess.Buffer(buffer)
+	}
+	return buffer, nil
+}
+
+// decodeFile - mp3/wav faylni berilgan formatga keltirib xotiraga yuklaydi
+func decodeFile(fullPath string, target beep.Format) (*beep.Buffer, error) {
+	f, err := os.Open(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("fayl topilmadi: %s", fullPath)
+		}
+		return nil, fmt.Errorf("fayl ochilmadi: %w", err)
+	}
+	defer f.Close()
+
+	var streamer beep.StreamSeekCloser
+	var format beep.Format
+
+	ext := filepath.Ext(fullPath)
+	switch ext {
+	case ".mp3":
+		streamer, format, err = mp3.Decode(f)
+	case ".wav":
+		streamer, format, err = wav.Decode(f)
+	default:
+		return nil, fmt.Errorf("format qo'llab-quvvatlanmaydi: %s", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode xato: %w", err)
+	}
+	defer streamer.Close()
+
+	var source beep.Streamer = streamer
+	if ext == ".wav" && format.Precision >= 2 {
+		// beep v1.1.0 wav dekoderi 16-bit namunani 1<<16-1 ga, 24-bit ni 1<<24-1 ga
+		// bo'ladi (to'g'risi 1<<15 va 1<<23), ya'ni wav kliplar 2 barobar (6 dB)
+		// sokin o'qiladi va mp3 kliplar yonida past eshitiladi. Shu yerda tuzatiladi;
+		// beep yangilanganda TestDecodeWAVLevel buni ko'rsatadi
+		source = withGain(source, 2)
+	}
+	if format.SampleRate != target.SampleRate {
+		source = beep.Resample(resampleQuality, format.SampleRate, target.SampleRate, source)
+	}
+
+	buffer := beep.NewBuffer(target)
+	buffer.Append(source)
+	if err := streamer.Err(); err != nil {
+		return nil, fmt.Errorf("decode xato: %w", err)
+	}
+	return buffer, nil
+}
+
+// mixClip - xotiradagi klip va uning balandligi
+type mixClip struct {
+	buffer *beep.Buffer
+	gain   float64
+}
+
+// mixSegments - bosqichlarni bitta oqimga yig'adi
+// segments[i] - bitta bosqichning kliplari ("yigirma", "besh")
+// Bosqichlar orasida gap sukunat, bosqich ichida crossfade ishlatiladi
+func mixSegments(segments [][]mixClip, rate beep.SampleRate, gap, crossfade time.Duration) *samples {
+	gapN := rate.N(gap)
+	fadeN := rate.N(crossfade)
+
+	var out [][2]float64
+	for i, segment := range segments {
+		if i > 0 && gapN > 0 {
+			out = append(out, make([][2]float64, gapN)...)
+		}
+
+		prevLen := 0
+		for j, part := range segment {
+			clip := readSamples(withGain(part.buffer.Streamer(0, part.buffer.Len()), part.gain), part.buffer.Len())
+			if j == 0 {
+				out = append(out, clip...)
+			} else {
+				out = crossfadeAppend(out, clip, min(fadeN, prevLen))
+			}
+			prevLen = len(clip)
+		}
+	}
+
+	return &samples{data: out}
+}
+
+// crossfadeAppend - oldingi klip oxiri va yangi klip boshini n namuna davomida ustma-ust qo'yadi
+func crossfadeAppend(out, clip [][2]float64, n int) [][2]float64 {
+	n = min(n, len(clip), len(out))
+	start := len(out) - n
+	for k := 0; k < n; k++ {
+		t := float64(k+1) / float64(n+1)
+		for ch := 0; ch < 2; ch++ {
+			out[start+k][ch] = out[start+k][ch]*(1-t) + clip[k][ch]*t
+		}
+	}
+	return append(out, clip[n:]...)
+}
+
+// amplify - tayyor oqimga umumiy ovoz balandligini qo'llaydi
+func amplify(stream beep.StreamSeeker, gain float64) beep.StreamSeeker {
+	return &samples{data: readSamples(withGain(stream, gain), stream.Len()-stream.Position())}
+}
+
+// readSamples - oqimdan n tagacha namunani nusxalab oladi
+func readSamples(streamer beep.Streamer, n int) [][2]float64 {
+	data := make([][2]float64, n)
+	for filled := 0; filled < len(data); {
+		n, ok := streamer.Stream(data[filled:])
+		filled += n
+		if !ok {
+			return data[:filled]
+		}
+	}
+	return data
+}
+
+// samples - xotiradagi tayyor e'lon (beep.StreamSeeker)
+type samples struct {
+	data [][2]float64
+	pos  int
+}
+
+func (s *samples) Stream(buf [][2]float64) (int, bool) {
+	if s.pos >= len(s.data) {
+		return 0, false
+	}
+	n := copy(buf, s.data[s.pos:])
+	s.pos += n
+	return n, true
+}
+
+func (s *samples) Err() error    { return nil }
+func (s *samples) Len() int      { return len(s.data) }
+func (s *samples) Position() int { return s.pos }
+
+func (s *samples) Seek(p int) error {
+	if p < 0 || p > len(s.data) {
+		return fmt.Errorf("seek %d: oraliqdan tashqarida [0, %d]", p, len(s.data))
+	}
+	s.pos = p
+	return nil
+}
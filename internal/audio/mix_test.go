@@ -0,0 +1,41 @@
+package audio
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/faiface/beep/wav"
+)
+
+func TestDecodeWAVLevel(t *testing.T) {
+	const level = 0.5
+	dir := writeTestPack(t, map[string]float64{"tone": level})
+	file := filepath.Join(dir, "tone.wav")
+
+	// beep ning o'zi 16-bit wav ni 2 barobar sokin o'qiydi
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	streamer, _, err := wav.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := readSamples(streamer, 1)
+	if len(raw) != 1 || math.Abs(raw[0][0]-level/2) > 1e-3 {
+		t.Fatalf("wav.Decode namunasi = %v, kutilgan ~%v (beep wav scaling o'zgargan - decodeFile dagi tuzatishni olib tashlang)", raw, level/2)
+	}
+
+	// decodeFile yozilgan balandlikni tiklaydi
+	buffer, err := decodeFile(file, outputFormat(testRate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := readSamples(buffer.Streamer(0, buffer.Len()), 1)
+	if math.Abs(got[0][0]-level) > 1e-3 || math.Abs(got[0][1]-level) > 1e-3 {
+		t.Fatalf("decodeFile namunasi = %v, kutilgan ~%v", got[0], level)
+	}
+}
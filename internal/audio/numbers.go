@@ -0,0 +1,70 @@
+package audio
+
+import "fmt"
+
+// MaxSpokenNumber - e'lon qilinadigan eng katta navbat raqami
+const MaxSpokenNumber = 9999
+
+// NumberClips - sonni o'zbekcha aytilishi bo'yicha klip tokenlariga ajratadi
+// Tokenlar kengaytmasiz: "numbers/20", "numbers/5" -> "yigirma besh"
+//
+// Qoidalar:
+//   - ming va yuz: 1 uchun faqat "ming"/"yuz", qolganlar uchun "<raqam> ming"/"<raqam> yuz"
+//   - o'nliklar oxirida kelsa "a" shakli ishlatiladi: 10 -> "10a", 120 -> "100", "20a"
+//   - birliklar oxirgi so'z bo'ladi: 25 -> "20", "5"
+//
+// has - ixtiyoriy: berilgan bo'lsa, "numbers/200" kabi tayyor yozuvlar
+// "2" + "100" o'rniga ishlatiladi. nil bo'lsa faqat asosiy kliplar ishlatiladi.
+func NumberClips(number int, has func(token string) bool) ([]string, error) {
+	if number <= 0 {
+		return nil, fmt.Errorf("noto'g'ri raqam: %d", number)
+	}
+	if number > MaxSpokenNumber {
+		return nil, fmt.Errorf("%d dan katta raqamlar e'lon qilinmaydi: %d", MaxSpokenNumber, number)
+	}
+
+	var clips []string
+
+	thousands := number / 1000
+	hundreds := number % 1000 / 100
+	tens := number % 100 / 10
+	units := number % 10
+
+	if thousands > 0 {
+		clips = append(clips, scaleClips(thousands, 1000, has)...)
+	}
+	if hundreds > 0 {
+		clips = append(clips, scaleClips(hundreds, 100, has)...)
+	}
+	if tens > 0 {
+		// O'nlik oxirgi so'z bo'lsa, tugallangan shakl: "o'n" emas "o'nta" kabi
+		if units == 0 {
+			clips = append(clips, fmt.Sprintf("numbers/%d0a", tens))
+		} else {
+			clips = append(clips, fmt.Sprintf("numbers/%d0", tens))
+		}
+	}
+	if units > 0 {
+		clips = append(clips, fmt.Sprintf("numbers/%d", units))
+	}
+
+	return clips, nil
+}
+
+// scaleClips - "ming" va "yuz" xonalari uchun kliplar
+// 1 -> ["numbers/100"], 3 -> ["numbers/3", "numbers/100"] yoki tayyor "numbers/300"
+// "ming" so'zi (numbers/1000) standart paketda yozilmagan: u yozib qo'yilmaguncha
+// manifestdagi "texts" orqali TTS aytadi, TTS bo'lmasa coverage uni yetishmayotgan deb ko'rsatadi
+func scaleClips(digit, scale int, has func(token string) bool) []string {
+	word := fmt.Sprintf("numbers/%d", scale)
+	if digit == 1 {
+		return []string{word}
+	}
+
+	compound := fmt.Sprintf("numbers/%d", digit*scale)
+	if has != nil && has(compound) {
+		return []string{compound}
+	}
+
+	return []string{fmt.Sprintf("numbers/%d", digit), word}
+}
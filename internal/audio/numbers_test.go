@@ -0,0 +1,45 @@
+package audio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNumberClips(t *testing.T) {
+	withCompound := func(token string) bool { return token == "numbers/300" }
+
+	tests := []struct {
+		number int
+		has    func(token string) bool
+		want   []string
+	}{
+		{1, nil, []string{"numbers/1"}},
+		{10, nil, []string{"numbers/10a"}},
+		{11, nil, []string{"numbers/10", "numbers/1"}},
+		{20, nil, []string{"numbers/20a"}},
+		{100, nil, []string{"numbers/100"}},
+		{115, nil, []string{"numbers/100", "numbers/10", "numbers/5"}},
+		{300, withCompound, []string{"numbers/300"}},
+		{300, nil, []string{"numbers/3", "numbers/100"}},
+		{1000, nil, []string{"numbers/1000"}},
+		{9999, nil, []string{"numbers/9", "numbers/1000", "numbers/9", "numbers/100", "numbers/90", "numbers/9"}},
+	}
+
+	for _, tt := range tests {
+		got, err := NumberClips(tt.number, tt.has)
+		if err != nil {
+			t.Fatalf("NumberClips(%d): %v", tt.number, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NumberClips(%d) = %v, kutilgan %v", tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestNumberClipsOutOfRange(t *testing.T) {
+	for _, number := range []int{0, -1, MaxSpokenNumber + 1} {
+		if clips, err := NumberClips(number, nil); err == nil {
+			t.Errorf("NumberClips(%d) = %v, xato kutilgan", number, clips)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package audio
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// ==============================
+// OVOZ PAKETLARI (SOUND PACK)
+// sounds/manifest.json mantiqiy tokenlarni ("numbers/20a", "phrases/raqam_egasi")
+// fayllarga bog'laydi va bir nechta til paketini tavsiflaydi.
+// Manifestda yo'q tokenlar eski qoida bo'yicha qidiriladi: <token>.mp3, keyin <token>.wav
+// ==============================
+
+const (
+	// ManifestFile - sounds papkasidagi manifest fayli
+	ManifestFile = "manifest.json"
+
+	// DefaultLanguage - manifest bo'lmasa yoki til ko'rsatilmasa ishlatiladigan paket
+	DefaultLanguage = "uz-Latn"
+)
+
+// SoundPack - bitta til uchun kliplar to'plami
+type SoundPack struct {
+	Dir   string            `json:"dir"`   // sounds papkasiga nisbatan, "" - ildiz
+	Clips map[string]string `json:"clips"` // token -> fayl (paket papkasiga nisbatan)
+	Texts map[string]string `json:"texts"` // token -> TTS matni (yozuv bo'lmaganda aytiladi)
+}
+
+// Manifest - sounds/manifest.json tarkibi
+//
+//	{
+//	  "default_language": "uz-Latn",
+//	  "packs": {
+//	    "uz-Latn": {"dir": "", "clips": {"numbers/100": "numbers/wav/100.wav"}},
+//	    "ru":      {"dir": "ru", "clips": {}}
+//	  }
+//	}
+type Manifest struct {
+	DefaultLanguage string                `json:"default_language"`
+	Packs           map[string]*SoundPack `json:"packs"`
+}
+
+// DefaultManifest - manifest fayli bo'lmaganda: bitta uz-Latn paketi, sounds ildizida
+// "ming" yozuvi paketda yo'q, TTS uni shu matn bilan aytadi
+func DefaultManifest() *Manifest {
+	return &Manifest{
+		DefaultLanguage: DefaultLanguage,
+		Packs: map[string]*SoundPack{
+			DefaultLanguage: {Texts: map[string]string{"numbers/1000": "ming"}},
+		},
+	}
+}
+
+// LoadManifest - basePath/manifest.json ni o'qiydi
+// Fayl bo'lmasa DefaultManifest qaytariladi
+func LoadManifest(basePath string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(basePath, ManifestFile))
+	if os.IsNotExist(err) {
+		return DefaultManifest(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("manifest o'qilmadi: %w", err)
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("manifest noto'g'ri: %w", err)
+	}
+
+	if len(manifest.Packs) == 0 {
+		manifest.Packs = DefaultManifest().Packs
+	}
+	if manifest.DefaultLanguage == "" {
+		manifest.DefaultLanguage = DefaultLanguage
+	}
+	if _, ok := manifest.Packs[manifest.DefaultLanguage]; !ok {
+		return nil, fmt.Errorf("manifest: standart til paketi topilmadi: %s", manifest.DefaultLanguage)
+	}
+	for language, pack := range manifest.Packs {
+		if pack == nil {
+			manifest.Packs[language] = &SoundPack{}
+		}
+	}
+
+	return manifest, nil
+}
+
+// Languages - mavjud til paketlari (alifbo tartibida)
+func (m *Manifest) Languages() []string {
+	languages := make([]string, 0, len(m.Packs))
+	for language := range m.Packs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Resolve - tokenni faylga bog'laydi (basePath ga nisbatan, "/" bilan)
+// language bo'sh bo'lsa standart til ishlatiladi
+// Fayl mavjudligi diskdan tekshiriladi (qamrov hisoboti, shablonlarni tekshirish uchun);
+// e'lon paytida AudioService kliplar indeksidan foydalanadi
+func (m *Manifest) Resolve(basePath, language, token string) (string, error) {
+	return m.resolve(language, token, func(rel string) bool {
+		_, err := os.Stat(filepath.Join(basePath, filepath.FromSlash(rel)))
+		return err == nil
+	})
+}
+
+// resolve - Resolve, fayl mavjudligini exists tekshiradi (rel - sounds papkasiga nisbatan)
+func (m *Manifest) resolve(language, token string, exists func(rel string) bool) (string, error) {
+	if language == "" {
+		language = m.DefaultLanguage
+	}
+	pack, ok := m.Packs[language]
+	if !ok {
+		return "", fmt.Errorf("til paketi topilmadi: %s", language)
+	}
+
+	// 1. Manifestdagi aniq fayl
+	if file, ok := pack.Clips[token]; ok {
+		rel := path.Join(pack.Dir, file)
+		if !exists(rel) {
+			return "", fmt.Errorf("manifestdagi fayl topilmadi: %s -> %s", token, rel)
+		}
+		return rel, nil
+	}
+
+	// 2. Nomlash qoidasi: avval .mp3, keyin .wav
+	for _, ext := range []string{".mp3", ".wav"} {
+		rel := path.Join(pack.Dir, token+ext)
+		if exists(rel) {
+			return rel, nil
+		}
+	}
+
+	return "", fmt.Errorf("audio fayl topilmadi: %s [%s] (.mp3 yoki .wav)", token, language)
+}
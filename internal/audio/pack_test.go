@@ -0,0 +1,82 @@
+package audio
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestExampleManifestClips - namunaviy manifestdagi har bir fayl repoda bor va eshitiladigan ovozga ega
+func TestExampleManifestClips(t *testing.T) {
+	soundsDir := filepath.Join("..", "..", "sounds")
+	data, err := os.ReadFile(filepath.Join(soundsDir, "manifest.example.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("manifest.example.json: %v", err)
+	}
+
+	format := outputFormat(testRate)
+	for language, pack := range manifest.Packs {
+		for token := range pack.Clips {
+			file, err := manifest.Resolve(soundsDir, language, token)
+			if err != nil {
+				t.Errorf("%s [%s]: %v", token, language, err)
+				continue
+			}
+			buffer, err := decodeFile(filepath.Join(soundsDir, filepath.FromSlash(file)), format)
+			if err != nil {
+				t.Errorf("%s -> %s: %v", token, file, err)
+				continue
+			}
+			samples := readSamples(buffer.Streamer(0, buffer.Len()), buffer.Len())
+			if level := peakLevel(samples); buffer.Len() == 0 || level < 0.01 {
+				t.Errorf("%s -> %s: bo'sh yoki sukunat (%v, cho'qqi %.3f)", token, file, format.SampleRate.D(buffer.Len()), level)
+			}
+		}
+	}
+}
+
+// TestPlanUsesClipIndex - Preload dan keyin reja tokenlarni diskdan emas, kliplar indeksidan qidiradi
+func TestPlanUsesClipIndex(t *testing.T) {
+	dir := writeTestPack(t, testCallClips)
+	service := newTestService(t, dir, NewMemorySink())
+	service.Preload()
+
+	// Fayl diskdan o'chsa ham indeksda bor: reja tuzishda os.Stat chaqirilmaydi
+	if err := os.Remove(filepath.Join(dir, "numbers", "5.wav")); err != nil {
+		t.Fatal(err)
+	}
+	if plan := service.Plan(Announcement{QueueNumber: "5", RoomNumber: "3"}); !plan.Complete {
+		t.Fatalf("reja to'liq emas: %v", plan.Missing)
+	}
+
+	// Indeksda yo'q fayl bir marta tekshiriladi va eslab qolinadi, ReloadClips yangilaydi
+	if service.hasClip("numbers/7") {
+		t.Fatal("numbers/7 hali yo'q")
+	}
+	writeTestClip(t, dir, "numbers/7")
+	if service.hasClip("numbers/7") {
+		t.Fatal("yo'q fayl natijasi eslab qolinishi kerak")
+	}
+	service.ReloadClips()
+	if !service.hasClip("numbers/7") || service.hasClip("numbers/5") {
+		t.Fatal("ReloadClips dan keyin indeks diskka mos bo'lishi kerak")
+	}
+}
+
+// writeTestClip - mavjud paketga bitta klip qo'shadi
+func writeTestClip(t *testing.T, dir, token string) {
+	t.Helper()
+	source := writeTestPack(t, map[string]float64{token: 0.6})
+	data, err := os.ReadFile(filepath.Join(source, filepath.FromSlash(token)+".wav"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(token)+".wav"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,408 @@
+package audio
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// ==============================
+// E'LON REJASI
+// Qaysi kliplar qaysi tartibda ijro etilishini oldindan hisoblaydi.
+// Reja ovoz kartasiz tuziladi, ijro alohida bosqich (PlayPlan).
+// ==============================
+
+// E'lon bosqichlari
+const (
+	StepNumber = "number" // Navbat raqami
+	StepPhrase = "phrase" // Tayyor ibora
+	StepRoom   = "room"   // Xona raqami
+	StepChime  = "chime"  // E'londan oldingi signal
+	StepDoctor = "doctor" // Shifokor ismi yoki bo'lim nomi
+	StepPrefix = "prefix" // Navbat harfi ("K-015" dagi K)
+)
+
+// Announcement - e'lon qilinadigan chaqiriq
+// Bo'lim va shifokor signal va e'lon shablonini tanlash uchun ishlatiladi
+type Announcement struct {
+	TicketID       string `json:"ticket_id,omitempty"` // Takroriy e'lonlarni aniqlash uchun
+	QueueNumber    string `json:"queue_number"`
+	RoomNumber     string `json:"room_number"`
+	DepartmentName string `json:"department_name,omitempty"`
+	DoctorID       string `json:"doctor_id,omitempty"`
+}
+
+// PlanClip - rejadagi bitta klip
+type PlanClip struct {
+	Token   string  `json:"token"`          // Mantiqiy nom, masalan "numbers/20a"
+	File    string  `json:"file,omitempty"` // Topilgan fayl (sounds papkasiga nisbatan)
+	Missing bool    `json:"missing,omitempty"`
+	Gain    float64 `json:"gain"`          // Klip balandligi (clip_gain, signal uchun chime_gain)
+	TTS     bool    `json:"tts,omitempty"` // Yozuv yo'q, TTS aytadi (reja tuzishda sintez qilinmaydi)
+
+	// TTSPending - TTS fayli hali keshda yo'q: File bo'sh, ijro paytida sintez qilinadi
+	TTSPending bool `json:"tts_pending,omitempty"`
+
+	language string // TTS sintezi uchun til paketi
+}
+
+// PlanStep - e'lonning bir bosqichi (raqam, ibora, xona)
+// Bosqichdagi birorta klip yetishmasa, bosqich ijro etilmaydi
+type PlanStep struct {
+	Name  string     `json:"name"`
+	Kind  string     `json:"kind"`
+	Clips []PlanClip `json:"clips"`
+	Error string     `json:"error,omitempty"`
+}
+
+// Playable - bosqichni ijro etish mumkinmi
+func (s PlanStep) Playable() bool {
+	if s.Error != "" || len(s.Clips) == 0 {
+		return false
+	}
+	for _, clip := range s.Clips {
+		if clip.Missing {
+			return false
+		}
+	}
+	return true
+}
+
+// AnnouncementPlan - bitta e'lon uchun tayyor reja
+type AnnouncementPlan struct {
+	Announcement
+	QueueCode
+	Template string     `json:"template"` // Tanlangan shablon nomi
+	Steps    []PlanStep `json:"steps"`
+	Error    string     `json:"error,omitempty"` // Navbat raqami noto'g'ri - e'lon qilinmaydi
+	Missing  []string   `json:"missing"`         // Yetishmayotgan tokenlar (takrorlanmaydi)
+	Complete bool       `json:"complete"`
+}
+
+// Files - ijro etiladigan fayllar tartib bilan (yetishmayotgan bosqichlarsiz)
+// Hali sintez qilinmagan TTS kliplarida fayl yo'q, ular ro'yxatga kirmaydi
+func (p AnnouncementPlan) Files() []string {
+	var files []string
+	for _, step := range p.Steps {
+		if !step.Playable() {
+			continue
+		}
+		for _, clip := range step.Clips {
+			if clip.TTSPending {
+				continue
+			}
+			files = append(files, clip.File)
+		}
+	}
+	return files
+}
+
+// Plan - navbat raqami va xona bo'yicha e'lon rejasini tuzadi
+// Ketma-ketlik bo'lim yoki shifokor shablonidan olinadi (template.go)
+// Hech narsa ijro etilmaydi, faqat fayllar mavjudligi tekshiriladi
+func (a *AudioService) Plan(req Announcement) AnnouncementPlan {
+	levels := a.Levels()
+	plan := AnnouncementPlan{
+		Announcement: req,
+		Missing:      []string{},
+	}
+
+	code, err := ParseQueueNumber(req.QueueNumber)
+	if err != nil {
+		plan.Error = err.Error()
+		return plan
+	}
+	plan.QueueCode = code
+
+	// Bo'lim uchun signal (sozlanmagan bo'lsa umumiy, u ham bo'sh bo'lsa signalsiz)
+	if chime := levels.ChimeFor(req.DepartmentName); chime != "" {
+		chimeStep := a.tokenStep("Signal", StepChime, chime)
+		for i := range chimeStep.Clips {
+			chimeStep.Clips[i].Gain *= levels.ChimeGain
+		}
+		plan.Steps = append(plan.Steps, chimeStep)
+	}
+
+	name, items := a.templates.Select(req)
+	plan.Template = name
+	for _, item := range items {
+		if step, ok := a.templateStep(item, req, code); ok {
+			plan.Steps = append(plan.Steps, step)
+		}
+	}
+
+	plan.summarize()
+	return plan
+}
+
+// summarize - Complete va Missing maydonlarini bosqichlardan hisoblaydi
+func (p *AnnouncementPlan) summarize() {
+	p.Complete = true
+	seen := make(map[string]bool)
+	for _, step := range p.Steps {
+		if !step.Playable() {
+			p.Complete = false
+		}
+		for _, clip := range step.Clips {
+			if clip.Missing && !seen[clip.Token] {
+				seen[clip.Token] = true
+				p.Missing = append(p.Missing, clip.Token)
+			}
+		}
+	}
+}
+
+// templateStep - shablonning bitta elementini bosqichga aylantiradi
+// Harfsiz navbat raqamida {prefix} bosqichi tushirib qoldiriladi (false)
+func (a *AudioService) templateStep(item string, req Announcement, code QueueCode) (PlanStep, bool) {
+	language, body := splitItem(item)
+	has := func(token string) bool {
+		_, err := a.findClipIn(language, token)
+		return err == nil
+	}
+
+	var step PlanStep
+	var tokens []string
+	var err error
+	switch body {
+	case SlotPrefix:
+		if code.Prefix == "" {
+			return PlanStep{}, false
+		}
+		step = PlanStep{Name: "Navbat harfi", Kind: StepPrefix}
+		tokens = prefixClips(code.Prefix, req.DepartmentName, has)
+	case SlotNumber:
+		step = PlanStep{Name: "Navbat raqami", Kind: StepNumber}
+		tokens, err = NumberClips(code.Number, has)
+	case SlotRoom:
+		// Xona: yozib olingan "<xona>-xona" fayli, bo'lmasa raqamdan yig'iladi
+		step = PlanStep{Name: "Xona raqami", Kind: StepRoom}
+		tokens, err = RoomClips(req.RoomNumber, has)
+	case SlotDoctor:
+		step = PlanStep{Name: "Shifokor", Kind: StepDoctor}
+		if req.DoctorID == "" {
+			err = fmt.Errorf("shifokor ko'rsatilmagan")
+		} else {
+			tokens = []string{nameToken("doctors", req.DoctorID)}
+		}
+	case SlotDepartment, SlotSpecialization:
+		step = PlanStep{Name: "Bo'lim", Kind: StepDoctor}
+		if req.DepartmentName == "" {
+			err = fmt.Errorf("bo'lim ko'rsatilmagan")
+		} else {
+			tokens = []string{nameToken("departments", req.DepartmentName)}
+		}
+	default:
+		step = PlanStep{Name: body, Kind: StepPhrase}
+		tokens = []string{body}
+	}
+	if body == SlotNumber || body == SlotRoom {
+		// Shablon tekshirilgan, lekin old qo'shimchasiz elementlar e'lon tilida:
+		// u o'zbekcha bo'lmasa, noto'g'ri so'zlar aytilmasligi uchun bosqich o'tkazib yuboriladi
+		if packLanguage, _ := a.ttsPack(language); !composesNumbers(packLanguage) {
+			err = fmt.Errorf("%s paketida raqamlarni yig'ib bo'lmaydi (faqat o'zbek paketi)", packLanguage)
+		}
+	}
+
+	if language != "" {
+		step.Name += " (" + language + ")"
+	}
+	if err != nil {
+		step.Error = err.Error()
+		return step, true
+	}
+	step.Clips = a.resolveClipsIn(language, tokens)
+	return step, true
+}
+
+// prefixClips - navbat harfi: "letters/k", harf yozilmagan bo'lsa bo'lim nomi
+func prefixClips(prefix, department string, has func(token string) bool) []string {
+	letters := letterClips(prefix)
+	for _, token := range letters {
+		if !has(token) && department != "" && has(nameToken("departments", department)) {
+			return []string{nameToken("departments", department)}
+		}
+	}
+	return letters
+}
+
+// tokenStep - bitta klipdan iborat bosqich
+func (a *AudioService) tokenStep(name, kind, token string) PlanStep {
+	return PlanStep{Name: name, Kind: kind, Clips: a.resolveClips([]string{token})}
+}
+
+// resolveClips - tokenlarni fayllarga va balandlik sozlamalariga bog'laydi
+func (a *AudioService) resolveClips(tokens []string) []PlanClip {
+	return a.resolveClipsIn("", tokens)
+}
+
+// resolveClipsIn - resolveClips, berilgan til paketi uchun
+func (a *AudioService) resolveClipsIn(language string, tokens []string) []PlanClip {
+	levels := a.Levels()
+	clips := make([]PlanClip, 0, len(tokens))
+	for _, token := range tokens {
+		file, tts, err := a.planToken(language, token)
+		clips = append(clips, PlanClip{
+			Token:      token,
+			File:       file,
+			Missing:    err != nil,
+			Gain:       levels.Gain(token),
+			TTS:        tts,
+			TTSPending: tts && err == nil && file == "",
+			language:   language,
+		})
+	}
+	return clips
+}
+
+// StepResult - bosqich ijrosi natijasi
+type StepResult struct {
+	Name   string `json:"name"`
+	Played bool   `json:"played"`
+	Error  string `json:"error,omitempty"`
+}
+
+// PlayPlan - tayyor rejani bitta uzluksiz oqim sifatida ijro etadi
+// Yetishmayotgan yoki o'qib bo'lmaydigan bosqichlar o'tkazib yuboriladi
+// va natijada xatosi bilan qaytariladi
+func (a *AudioService) PlayPlan(plan AnnouncementPlan) ([]StepResult, error) {
+	results, _, err := a.playPlan(a.sink, plan)
+	return results, err
+}
+
+// playRoute - e'lon qaysi chiqishga ketgani (task va tarix uchun)
+type playRoute struct {
+	sink     string
+	fallback bool // Asosiy qurilma ishlamadi, zaxira sink ishlatildi
+}
+
+func (a *AudioService) playPlan(sink Sink, plan AnnouncementPlan) ([]StepResult, playRoute, error) {
+	stream, results, err := a.RenderPlan(plan)
+	if err != nil {
+		return results, playRoute{}, err
+	}
+
+	// Umumiy ovoz faqat zaldagi karnay uchun (tungi rejim shu yerda qo'llanadi),
+	// brauzerlarga yuboriladigan WAV o'zgarmaydi
+	if volume := a.Levels().VolumeAt(time.Now()); volume != 1 {
+		stream = amplify(stream, volume)
+	}
+
+	playStart := time.Now()
+	var route playRoute
+	route.sink, route.fallback, err = playOn(sink, stream, a.format)
+	if err == nil && route.fallback && route.sink == SinkNull {
+		err = ErrSilentFallback
+	}
+	if err != nil {
+		// Oqim oxirigacha chiqmadi - hech bir bosqich eshitildi deb hisoblanmaydi
+		for i := range results {
+			if results[i].Played {
+				results[i].Played = false
+				results[i].Error = err.Error()
+			}
+		}
+		return results, route, err
+	}
+	log.Printf("✅ %d bosqich ijro etildi (%v, %s)", playedSteps(results), time.Since(playStart), route.sink)
+	return results, route, nil
+}
+
+// RenderPlan - rejani ijro etmasdan bitta oqimga yig'adi
+// Speaker, WAV fayl va brauzer uchun render bir xil natija beradi
+func (a *AudioService) RenderPlan(plan AnnouncementPlan) (beep.StreamSeeker, []StepResult, error) {
+	var segments [][]mixClip
+	results := make([]StepResult, 0, len(plan.Steps))
+
+	for _, step := range plan.Steps {
+		result := StepResult{Name: step.Name}
+		if !step.Playable() {
+			result.Error = step.Error
+			if result.Error == "" {
+				result.Error = "audio fayl topilmadi"
+			}
+			log.Printf("⚠️ %s o'tkazib yuborildi: %s", step.Name, result.Error)
+			results = append(results, result)
+			continue
+		}
+
+		buffers, err := a.decodeStep(step)
+		if err != nil {
+			log.Printf("⚠️ %s xato: %v", step.Name, err)
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		segments = append(segments, buffers)
+		result.Played = true
+		results = append(results, result)
+	}
+
+	if len(segments) == 0 {
+		return nil, results, fmt.Errorf("ijro etiladigan bosqich yo'q")
+	}
+
+	return mixSegments(segments, a.format.SampleRate, a.options.Gap, a.options.Crossfade), results, nil
+}
+
+// playedSteps - ijro etilgan bosqichlar soni
+func playedSteps(results []StepResult) int {
+	count := 0
+	for _, result := range results {
+		if result.Played {
+			count++
+		}
+	}
+	return count
+}
+
+// RenderAnnouncement - e'lonni WAV faylga yozadi va ID sini qaytaradi
+// Faqat Options.Renders sozlangan bo'lsa ishlaydi
+func (a *AudioService) RenderAnnouncement(req Announcement) (string, AnnouncementPlan, error) {
+	plan := a.Plan(req)
+	if plan.Error != "" {
+		return "", plan, fmt.Errorf("%s", plan.Error)
+	}
+	if a.renders == nil {
+		return "", plan, fmt.Errorf("e'lonlarni render qilish o'chirilgan")
+	}
+
+	stream, _, err := a.RenderPlan(plan)
+	if err != nil {
+		return "", plan, err
+	}
+	id, err := a.renders.Save(stream, a.format)
+	return id, plan, err
+}
+
+// RenderedPath - render qilingan e'lon fayli
+func (a *AudioService) RenderedPath(id string) (string, error) {
+	if a.renders == nil {
+		return "", os.ErrNotExist
+	}
+	return a.renders.Path(id)
+}
+
+// decodeStep - bosqichdagi barcha kliplarni xotiradan oladi
+func (a *AudioService) decodeStep(step PlanStep) ([]mixClip, error) {
+	clips := make([]mixClip, 0, len(step.Clips))
+	for _, clip := range step.Clips {
+		// TTS fayli reja tuzilganda yaratilmaydi, faqat haqiqiy render paytida
+		if clip.TTSPending {
+			file, err := a.synthesize(clip.language, clip.Token)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", clip.Token, err)
+			}
+			clip.File = file
+		}
+		buffer, err := a.clip(clip.File)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", clip.Token, err)
+		}
+		clips = append(clips, mixClip{buffer: buffer, gain: clip.Gain})
+	}
+	return clips, nil
+}
@@ -0,0 +1,240 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// levelRun - yozuvdagi bir xil balandlikdagi bo'lak (0 - sukunat)
+type levelRun struct {
+	level  float64
+	length int
+}
+
+// levelRuns - yozuvni bir xil balandlikdagi bo'laklarga ajratadi (chap kanal bo'yicha)
+func levelRuns(data [][2]float64) []levelRun {
+	var runs []levelRun
+	for _, sample := range data {
+		if n := len(runs); n > 0 && math.Abs(runs[n-1].level-sample[0]) < 1e-3 {
+			runs[n-1].length++
+			continue
+		}
+		runs = append(runs, levelRun{level: sample[0], length: 1})
+	}
+	return runs
+}
+
+func TestPlanOrder(t *testing.T) {
+	service := newTestService(t, writeTestPack(t, testCallClips), NewMemorySink())
+
+	plan := service.Plan(Announcement{QueueNumber: "5", RoomNumber: "3"})
+	if plan.Error != "" || !plan.Complete {
+		t.Fatalf("reja to'liq emas: error=%q, missing=%v", plan.Error, plan.Missing)
+	}
+
+	var kinds []string
+	for _, step := range plan.Steps {
+		kinds = append(kinds, step.Kind)
+	}
+	wantKinds := []string{StepNumber, StepPhrase, StepRoom, StepPhrase}
+	if !reflect.DeepEqual(kinds, wantKinds) {
+		t.Errorf("bosqichlar = %v, kutilgan %v", kinds, wantKinds)
+	}
+
+	wantFiles := []string{"numbers/5.wav", "phrases/raqam_egasi.wav", "numbers/3-xona.wav", "phrases/honaga_kelishin.wav"}
+	if files := plan.Files(); !reflect.DeepEqual(files, wantFiles) {
+		t.Errorf("fayllar = %v, kutilgan %v", files, wantFiles)
+	}
+}
+
+func TestPlanMissingClip(t *testing.T) {
+	clips := map[string]float64{"numbers/5": 0.2, "numbers/3-xona": 0.4}
+	service := newTestService(t, writeTestPack(t, clips), NewMemorySink())
+
+	plan := service.Plan(Announcement{QueueNumber: "5", RoomNumber: "3"})
+	if plan.Complete {
+		t.Fatal("iboralar yo'q, reja to'liq bo'lmasligi kerak")
+	}
+	want := []string{"phrases/raqam_egasi", "phrases/honaga_kelishin"}
+	if !reflect.DeepEqual(plan.Missing, want) {
+		t.Errorf("Missing = %v, kutilgan %v", plan.Missing, want)
+	}
+}
+
+func TestPlayAnnouncementGapsAndChime(t *testing.T) {
+	clips := map[string]float64{"chime": 0.8}
+	for token, level := range testCallClips {
+		clips[token] = level
+	}
+	memory := NewMemorySink()
+	service := newTestService(t, writeTestPack(t, clips), memory)
+
+	levels := DefaultLevels()
+	levels.Chime = "chime"
+	levels.ChimeGain = 0.5
+	if err := service.SetLevels(levels); err != nil {
+		t.Fatal(err)
+	}
+
+	steps, err := service.PlayAnnouncement(Announcement{QueueNumber: "5", RoomNumber: "3"})
+	if err != nil {
+		t.Fatalf("PlayAnnouncement: %v", err)
+	}
+	if playedSteps(steps) != 5 {
+		t.Fatalf("%d/5 bosqich ijro etildi: %+v", playedSteps(steps), steps)
+	}
+
+	recordings := memory.Recordings()
+	if len(recordings) != 1 {
+		t.Fatalf("yozuvlar soni = %d, kutilgan 1", len(recordings))
+	}
+
+	// Signal (chime_gain bilan), keyin bosqichlar shablon tartibida, orasida DefaultGap sukunat
+	clip, gap := testRate.N(testClipLength), testRate.N(DefaultGap)
+	want := []levelRun{
+		{0.4, clip}, {0, gap},
+		{0.2, clip}, {0, gap},
+		{0.3, clip}, {0, gap},
+		{0.4, clip}, {0, gap},
+		{0.5, clip},
+	}
+	got := levelRuns(recordings[0].Samples)
+	if len(got) != len(want) {
+		t.Fatalf("bo'laklar = %v, kutilgan %v", got, want)
+	}
+	for i := range want {
+		if math.Abs(got[i].level-want[i].level) > 1e-3 || got[i].length != want[i].length {
+			t.Errorf("%d-bo'lak = %+v, kutilgan %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestQueuePlaysBroadcastFirst(t *testing.T) {
+	clips := map[string]float64{"numbers/7": 0.7}
+	for token, level := range testCallClips {
+		clips[token] = level
+	}
+	memory := NewMemorySink()
+	service := newTestService(t, writeTestPack(t, clips), memory)
+	queue := NewAudioQueueService(service, 1, 0).WithZone("test", memory)
+
+	// Ishga tushirishdan oldin: xabar kutayotgan chaqiriqlardan oldinga qo'yiladi
+	first, err := queue.AddTask(Announcement{QueueNumber: "5", RoomNumber: "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last, err := queue.AddTask(Announcement{QueueNumber: "7", RoomNumber: "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := queue.Broadcast([]string{"phrases/raqam_egasi"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	queue.Start()
+	defer queue.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, id := range []string{first.ID, last.ID} {
+		task, err := queue.Wait(ctx, id)
+		if err != nil || task.State != TaskPlayed {
+			t.Fatalf("task %s: state=%s, err=%v", id, task.State, err)
+		}
+		if task.Sink != SinkMemory || task.Fallback {
+			t.Errorf("task %s: sink=%q fallback=%v", id, task.Sink, task.Fallback)
+		}
+	}
+
+	var order []float64
+	for _, recording := range memory.Recordings() {
+		order = append(order, math.Round(recording.Samples[0][0]*10)/10)
+	}
+	if want := []float64{0.3, 0.2, 0.7}; !reflect.DeepEqual(order, want) {
+		t.Errorf("ijro tartibi (birinchi klip balandligi) = %v, kutilgan %v", order, want)
+	}
+}
+
+func TestPlanMarksPendingTTS(t *testing.T) {
+	stub := buildTTSStub(t)
+	tts, err := NewTTS(stub, []string{"{out}", "{text}"}, t.TempDir(), 10*time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clips := map[string]float64{}
+	for token, level := range testCallClips {
+		if token != "phrases/honaga_kelishin" {
+			clips[token] = level
+		}
+	}
+	options := DefaultOptions()
+	options.SampleRate = testRate
+	options.Sink = NewMemorySink()
+	options.TTS = tts
+	service := NewAudioService(writeTestPack(t, clips), options)
+
+	plan := service.Plan(Announcement{QueueNumber: "5", RoomNumber: "3"})
+	if !plan.Complete {
+		t.Fatalf("TTS bilan reja to'liq bo'lishi kerak: %v", plan.Missing)
+	}
+	last := plan.Steps[len(plan.Steps)-1].Clips[0]
+	if !last.TTS || !last.TTSPending || last.File != "" {
+		t.Fatalf("sintez qilinmagan TTS klipi = %+v", last)
+	}
+	for _, file := range plan.Files() {
+		if file == "" {
+			t.Fatalf("Files() da bo'sh fayl nomi: %v", plan.Files())
+		}
+	}
+	if len(plan.Files()) != 3 {
+		t.Fatalf("Files() = %v, kutilgan 3 ta yozilgan klip", plan.Files())
+	}
+
+	// Ijrodan keyin fayl keshda: reja uni oddiy TTS klipi sifatida ko'rsatadi
+	if _, err := service.PlayPlan(plan); err != nil {
+		t.Fatalf("PlayPlan: %v", err)
+	}
+	last = service.Plan(Announcement{QueueNumber: "5", RoomNumber: "3"}).Steps[len(plan.Steps)-1].Clips[0]
+	if !last.TTS || last.TTSPending || last.File == "" {
+		t.Fatalf("sintez qilingan TTS klipi = %+v", last)
+	}
+}
+
+func TestTemplateRejectsNumbersInOtherLanguages(t *testing.T) {
+	for _, item := range []string{"ru:{number}", "ru:{room}", "en:{number}"} {
+		templates := &Templates{Named: map[string][]string{"ikki_tilli": {SlotNumber, item}}}
+		if err := templates.Validate(); err == nil {
+			t.Errorf("%q qabul qilinmasligi kerak", item)
+		}
+	}
+	for _, item := range []string{"uz-Cyrl:{number}", "ru:{prefix}", "ru:phrases/nomer"} {
+		templates := &Templates{Named: map[string][]string{"ikki_tilli": {SlotNumber, item}}}
+		if err := templates.Validate(); err != nil {
+			t.Errorf("%q: %v", item, err)
+		}
+	}
+}
+
+func TestPlanSkipsNumbersInOtherLanguage(t *testing.T) {
+	dir := writeTestPack(t, testCallClips)
+	manifest := `{"default_language": "ru", "packs": {"ru": {"dir": ""}}}`
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	service := newTestService(t, dir, NewMemorySink())
+
+	// Kliplar bor, lekin ruscha paketda o'zbekcha qoidalar bilan raqam yig'ilmaydi
+	plan := service.Plan(Announcement{QueueNumber: "5", RoomNumber: "3"})
+	for _, step := range plan.Steps {
+		if step.Kind == StepNumber || step.Kind == StepRoom {
+			if step.Error == "" || step.Playable() {
+				t.Errorf("%s bosqichi ijro etilmasligi kerak: %+v", step.Name, step)
+			}
+		}
+	}
+}
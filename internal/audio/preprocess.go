@@ -0,0 +1,339 @@
+package audio
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// ==============================
+// KLIPLARNI TOZALASH (PREPROCESS)
+// Yozuvlar har xil balandlikda va boshida/oxirida uzun sukunat bilan keladi,
+// shuning uchun e'lon uzuq-yuluq eshitiladi. Klip yuklanganda (yoki
+// NormalizePack orqali bir marta) boshidagi va oxiridagi sukunat kesiladi,
+// nutq qismining RMS darajasi bir xil qiymatga keltiriladi.
+// ==============================
+
+const (
+	// Tavsiya etilgan qiymatlar (sozlamalarda berilmasa normalize-pack shularni ishlatadi)
+	DefaultTrimDB    = -45.0
+	DefaultTrimPad   = 30 * time.Millisecond
+	DefaultTargetDB  = -20.0
+	DefaultMaxGainDB = 12.0
+
+	// levelWindow - darajani o'lchash oynasi: bitta "chiqillash" sukunatni buzmasligi uchun
+	levelWindow = 10 * time.Millisecond
+
+	// peakCeiling - kuchaytirishdan keyingi eng katta namuna (-1 dBFS), qirqilish bo'lmasligi uchun
+	peakCeiling = 0.891
+
+	// gateDB - TrimDB berilmaganda RMS o'lchashda hisobga olinmaydigan sukunat darajasi
+	gateDB = -50.0
+)
+
+// Preprocess - klipni tozalash sozlamalari; nol qiymat - shu bosqich o'chirilgan
+type Preprocess struct {
+	TrimDB    float64       // Shundan (dBFS) past boshlang'ich va oxirgi qism kesiladi, masalan -45
+	TrimPad   time.Duration // Kesilgan joyda qoldiriladigan sukunat (so'z boshi yutilmasligi uchun)
+	TargetDB  float64       // Nutq qismining RMS darajasi (dBFS), masalan -20
+	MaxGainDB float64       // Sokin klipni ko'pi bilan shuncha kuchaytirish (shovqin ko'tarilmasligi uchun)
+}
+
+// PreprocessResult - bitta klipga nima qilingani
+type PreprocessResult struct {
+	Before  time.Duration `json:"before"`
+	After   time.Duration `json:"after"`
+	LevelDB float64       `json:"level_db"` // Tozalashdan oldingi nutq darajasi
+	GainDB  float64       `json:"gain_db"`
+}
+
+// Enabled - birorta bosqich yoqilganmi
+func (p Preprocess) Enabled() bool {
+	return p.TrimDB != 0 || p.TargetDB != 0
+}
+
+// Validate - darajalar dBFS da (manfiy), kuchaytirish chegarasi musbat
+func (p Preprocess) Validate() error {
+	if p.TrimDB > 0 || math.IsNaN(p.TrimDB) {
+		return fmt.Errorf("trim_db manfiy bo'lishi kerak (dBFS): %v", p.TrimDB)
+	}
+	if p.TargetDB > 0 || math.IsNaN(p.TargetDB) {
+		return fmt.Errorf("target_db manfiy bo'lishi kerak (dBFS): %v", p.TargetDB)
+	}
+	if p.MaxGainDB < 0 || math.IsNaN(p.MaxGainDB) {
+		return fmt.Errorf("max_gain_db manfiy bo'lmasligi kerak: %v", p.MaxGainDB)
+	}
+	if p.TrimPad < 0 {
+		return fmt.Errorf("trim_pad manfiy bo'lmasligi kerak: %v", p.TrimPad)
+	}
+	return nil
+}
+
+// Buffer - xotiradagi klipni tozalab yangi buffer qaytaradi
+func (p Preprocess) Buffer(buffer *beep.Buffer) (*beep.Buffer, PreprocessResult) {
+	rate := buffer.Format().SampleRate
+	data, result := p.apply(readSamples(buffer.Streamer(0, buffer.Len()), buffer.Len()), rate)
+
+	out := beep.NewBuffer(buffer.Format())
+	out.Append(&samples{data: data})
+	return out, result
+}
+
+// apply - sukunatni kesadi, keyin nutq darajasini TargetDB ga keltiradi
+func (p Preprocess) apply(data [][2]float64, rate beep.SampleRate) ([][2]float64, PreprocessResult) {
+	result := PreprocessResult{Before: rate.D(len(data))}
+	window := max(rate.N(levelWindow), 1)
+	levels := windowLevels(data, window)
+
+	if p.TrimDB != 0 {
+		data = trimSilence(data, levels, window, p.TrimDB, rate.N(p.TrimPad))
+		levels = windowLevels(data, window)
+	}
+
+	gate := gateDB
+	if p.TrimDB != 0 {
+		gate = p.TrimDB
+	}
+	result.LevelDB = gatedLevel(levels, gate)
+
+	if p.TargetDB != 0 && !math.IsInf(result.LevelDB, -1) {
+		gainDB := min(p.TargetDB-result.LevelDB, p.MaxGainDB)
+		if peak := peakLevel(data); peak > 0 {
+			gainDB = min(gainDB, toDB(peakCeiling/peak))
+		}
+		if math.Abs(gainDB) >= 0.1 {
+			scale(data, math.Pow(10, gainDB/20))
+			result.GainDB = gainDB
+		}
+	}
+
+	result.After = rate.D(len(data))
+	return data, result
+}
+
+// windowLevels - har bir oyna uchun o'rtacha kvadrat (ikki kanal birga)
+func windowLevels(data [][2]float64, window int) []float64 {
+	levels := make([]float64, 0, len(data)/window+1)
+	for start := 0; start < len(data); start += window {
+		end := min(start+window, len(data))
+		sum := 0.0
+		for _, s := range data[start:end] {
+			sum += s[0]*s[0] + s[1]*s[1]
+		}
+		levels = append(levels, sum/float64(2*(end-start)))
+	}
+	return levels
+}
+
+// trimSilence - thresholdDB dan past boshlang'ich va oxirgi oynalarni kesadi
+// Butun klip sukunat bo'lsa o'zgarmaydi (yozuv buzilgan bo'lishi mumkin, kesib yubormaymiz)
+func trimSilence(data [][2]float64, levels []float64, window int, thresholdDB float64, pad int) [][2]float64 {
+	threshold := math.Pow(10, thresholdDB/10) // O'rtacha kvadrat bilan solishtiriladi
+	first, last := -1, -1
+	for i, level := range levels {
+		if level >= threshold {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return data
+	}
+
+	start := max(first*window-pad, 0)
+	end := min((last+1)*window+pad, len(data))
+	return data[start:end]
+}
+
+// gatedLevel - gateDB dan baland oynalar RMS darajasi (dBFS); nutq orasidagi pauzalar hisobga olinmaydi
+func gatedLevel(levels []float64, gateDB float64) float64 {
+	gate := math.Pow(10, gateDB/10)
+	sum, n := 0.0, 0
+	for _, level := range levels {
+		if level >= gate {
+			sum += level
+			n++
+		}
+	}
+	if n == 0 {
+		return math.Inf(-1)
+	}
+	return 10 * math.Log10(sum/float64(n))
+}
+
+// peakLevel - eng katta namuna qiymati
+func peakLevel(data [][2]float64) float64 {
+	peak := 0.0
+	for _, s := range data {
+		peak = max(peak, math.Abs(s[0]), math.Abs(s[1]))
+	}
+	return peak
+}
+
+func scale(data [][2]float64, gain float64) {
+	for i := range data {
+		data[i][0] *= gain
+		data[i][1] *= gain
+	}
+}
+
+func toDB(ratio float64) float64 {
+	return 20 * math.Log10(ratio)
+}
+
+// ==============================
+// TOZALANGAN WAV PAKET
+// Kliplarni har safar yuklashda tozalamaslik uchun: sounds papkasi bir marta
+// tozalanib, xuddi shu tuzilishdagi WAV paketga yoziladi (cmd/normalize-pack).
+// Manifestdagi .mp3 fayllar .wav ga almashtiriladi.
+// ==============================
+
+// PackReport - NormalizePack natijasi
+type PackReport struct {
+	Clips   int           `json:"clips"`
+	Skipped []string      `json:"skipped,omitempty"`
+	Failed  []PackFailure `json:"failed,omitempty"` // O'qib yoki yozib bo'lmagan kliplar (paketga kirmadi)
+	Trimmed time.Duration `json:"trimmed"`
+}
+
+// PackFailure - tozalanmagan klip va sababi
+type PackFailure struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+// NormalizePack - srcDir dagi barcha mp3/wav kliplarni tozalab dstDir ga WAV qilib yozadi
+// onClip har bir yozilgan klip uchun chaqiriladi (nil bo'lishi mumkin).
+// Buzilgan klip butun paketni to'xtatmaydi: report.Failed ga yoziladi va keyingisiga o'tiladi
+func NormalizePack(srcDir, dstDir string, rate beep.SampleRate, p Preprocess, onClip func(rel string, result PreprocessResult)) (PackReport, error) {
+	var report PackReport
+	if err := p.Validate(); err != nil {
+		return report, err
+	}
+	src, err := filepath.Abs(srcDir)
+	if err != nil {
+		return report, err
+	}
+	dst, err := filepath.Abs(dstDir)
+	if err != nil {
+		return report, err
+	}
+	if src == dst {
+		// .mp3 va .wav yonma-yon qolsa, nomlash qoidasi bo'yicha eski .mp3 aytiladi
+		return report, fmt.Errorf("tozalangan paket boshqa papkaga yozilishi kerak: %s", dstDir)
+	}
+
+	format := outputFormat(rate)
+	err = filepath.WalkDir(srcDir, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if abs, _ := filepath.Abs(file); abs == dst {
+				return filepath.SkipDir // sounds/normalized kabi ichki papka
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(file))
+		if ext != ".mp3" && ext != ".wav" {
+			return nil
+		}
+		rel, err := filepath.Rel(srcDir, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		// Ikkalasi bo'lsa .mp3 ishlatiladi (Manifest.Resolve), .wav paketga kirmaydi
+		if ext == ".wav" {
+			if _, err := os.Stat(strings.TrimSuffix(file, filepath.Ext(file)) + ".mp3"); err == nil {
+				report.Skipped = append(report.Skipped, rel)
+				return nil
+			}
+		}
+
+		buffer, err := decodeFile(file, format)
+		if err != nil {
+			report.Failed = append(report.Failed, PackFailure{File: rel, Error: err.Error()})
+			return nil
+		}
+		buffer, result := p.Buffer(buffer)
+
+		out := wavName(rel)
+		if err := writePackClip(filepath.Join(dstDir, filepath.FromSlash(out)), buffer, format); err != nil {
+			report.Failed = append(report.Failed, PackFailure{File: rel, Error: err.Error()})
+			return nil
+		}
+		report.Clips++
+		report.Trimmed += result.Before - result.After
+		if onClip != nil {
+			onClip(rel, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	if err := writePackManifest(srcDir, dstDir); err != nil {
+		return report, err
+	}
+	log.Printf("🎚️ Tozalangan paket: %s (%d ta klip, %d ta xato, %v sukunat kesildi)", dstDir, report.Clips, len(report.Failed), report.Trimmed.Round(time.Millisecond))
+	return report, nil
+}
+
+// writePackManifest - manifestni nusxalaydi, kliplar fayllarini .wav ga almashtiradi
+func writePackManifest(srcDir, dstDir string) error {
+	if _, err := os.Stat(filepath.Join(srcDir, ManifestFile)); os.IsNotExist(err) {
+		return nil
+	}
+	manifest, err := LoadManifest(srcDir)
+	if err != nil {
+		return err
+	}
+	for _, pack := range manifest.Packs {
+		for token, file := range pack.Clips {
+			pack.Clips[token] = wavName(file)
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(dstDir, ManifestFile)
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("manifest yozilmadi: %w", err)
+	}
+	return os.Rename(tmp, target)
+}
+
+// writePackClip - klipni vaqtinchalik faylga yozib, keyin almashtiradi
+func writePackClip(target string, buffer *beep.Buffer, format beep.Format) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	tmp := target + ".tmp"
+	if err := writeWAV(tmp, buffer.Streamer(0, buffer.Len()), format); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, target)
+}
+
+// wavName - "numbers/20.mp3" -> "numbers/20.wav"
+func wavName(rel string) string {
+	return strings.TrimSuffix(rel, path.Ext(rel)) + ".wav"
+}
@@ -0,0 +1,31 @@
+package audio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizePackContinuesAfterBrokenClip(t *testing.T) {
+	src := writeTestPack(t, map[string]float64{"numbers/1": 0.05, "numbers/2": 0.05})
+	if err := os.WriteFile(filepath.Join(src, "numbers", "3.mp3"), []byte("bu mp3 emas"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(t.TempDir(), "normalized")
+
+	report, err := NormalizePack(src, dst, testRate, Preprocess{TargetDB: DefaultTargetDB, MaxGainDB: DefaultMaxGainDB}, nil)
+	if err != nil {
+		t.Fatalf("NormalizePack: %v", err)
+	}
+	if report.Clips != 2 {
+		t.Errorf("Clips = %d, kutilgan 2", report.Clips)
+	}
+	if len(report.Failed) != 1 || report.Failed[0].File != "numbers/3.mp3" {
+		t.Fatalf("Failed = %+v, kutilgan numbers/3.mp3", report.Failed)
+	}
+	for _, name := range []string{"1.wav", "2.wav"} {
+		if _, err := os.Stat(filepath.Join(dst, "numbers", name)); err != nil {
+			t.Errorf("numbers/%s yozilmadi: %v", name, err)
+		}
+	}
+}
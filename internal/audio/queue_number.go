@@ -0,0 +1,34 @@
+package audio
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// queueNumberPattern - "K-015", "K015", "K 15", "015"
+var queueNumberPattern = regexp.MustCompile(`^([A-Za-z]{0,3})[-\s]?(\d{1,4})$`)
+
+// QueueCode - navbat raqamining qismlari: bo'lim harfi va raqam
+// "K-015" va "T-015" bir xil eshitilmasligi uchun harf ham aytiladi
+type QueueCode struct {
+	Prefix string `json:"prefix,omitempty"` // Katta harflarda: "K"
+	Number int    `json:"number"`
+}
+
+// ParseQueueNumber - navbat raqamini ajratadi
+// Noto'g'ri formatdagi yoki nol raqam xato qaytaradi (jimgina "nol" deb e'lon qilinmaydi)
+func ParseQueueNumber(queueNumber string) (QueueCode, error) {
+	match := queueNumberPattern.FindStringSubmatch(strings.TrimSpace(queueNumber))
+	if match == nil {
+		return QueueCode{}, fmt.Errorf("navbat raqami noto'g'ri: %q", queueNumber)
+	}
+
+	number, err := strconv.Atoi(match[2])
+	if err != nil || number <= 0 || number > MaxSpokenNumber {
+		return QueueCode{}, fmt.Errorf("navbat raqami 1..%d oralig'ida bo'lishi kerak: %q", MaxSpokenNumber, queueNumber)
+	}
+
+	return QueueCode{Prefix: strings.ToUpper(match[1]), Number: number}, nil
+}
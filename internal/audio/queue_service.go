@@ -1,36 +1,183 @@
 package audio
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"sync"
 	"time"
 )
 
+// DefaultQueueCapacity - navbatda kutishi mumkin bo'lgan tasklar
+const DefaultQueueCapacity = 100
+
+// DefaultDedupWindow - bir chipta uchun takroriy e'lonlar birlashtiriladigan oraliq
+const DefaultDedupWindow = 10 * time.Second
+
+// DefaultTaskHistory - holati so'ralishi mumkin bo'lgan tugagan tasklar soni
+const DefaultTaskHistory = 500
+
+// Task holatlari
+const (
+	TaskQueued    = "queued"    // Navbatda kutmoqda
+	TaskPlaying   = "playing"   // Ijro etilmoqda
+	TaskPlayed    = "played"    // Barcha bosqichlar eshitildi
+	TaskPartial   = "partial"   // Ba'zi bosqichlar o'tkazib yuborildi
+	TaskFailed    = "failed"    // Hech narsa eshitilmadi
+	TaskDropped   = "dropped"   // Navbat to'la edi, qo'shilmadi
+	TaskCancelled = "cancelled" // Ijrodan oldin bekor qilindi
+)
+
+// Task turlari
+const (
+	TaskKindCall      = "call"      // Oddiy chaqiriq
+	TaskKindRepeat    = "repeat"    // Tarixdan qayta e'lon
+	TaskKindBroadcast = "broadcast" // Favqulodda / umumiy xabar
+	TaskKindSchedule  = "schedule"  // Jadval bo'yicha xabar (tushlik, yopilish)
+)
+
+// ErrQueueFull - navbat to'la, task qo'shilmadi
+var ErrQueueFull = errors.New("audio navbati to'la")
+
+// ErrTaskNotFound - task topilmadi (yoki juda eski)
+var ErrTaskNotFound = errors.New("task topilmadi")
+
 // 🎯 AUDIO TASK STRUCTURE
 type AudioTask struct {
-	QueueNumber string
-	RoomNumber  string
-	Timestamp   time.Time
-	Priority    int // 1 - High, 2 - Medium, 3 - Low
+	ID string `json:"id"`
+	Announcement
+	Kind       string       `json:"kind"`
+	Items      []string     `json:"items,omitempty"`       // Umumiy xabar tokenlari (broadcast, schedule)
+	ScheduleID string       `json:"schedule_id,omitempty"` // Jadval bo'yicha xabar uchun
+	Zone       string       `json:"zone,omitempty"`        // Qaysi zona navbatida
+	Timestamp  time.Time    `json:"timestamp"`
+	Priority   int          `json:"priority"` // 1 - High, 2 - Medium, 3 - Low
+	State      string       `json:"state"`
+	Error      string       `json:"error,omitempty"`
+	Steps      []StepResult `json:"steps,omitempty"`    // Har bir bosqich natijasi
+	Sink       string       `json:"sink,omitempty"`     // E'lonni haqiqatda qabul qilgan chiqish
+	Fallback   bool         `json:"fallback,omitempty"` // Asosiy qurilma ishlamadi, zaxiraga ketdi
+	StartedAt  *time.Time   `json:"started_at,omitempty"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+
+	done chan struct{} // Task tugaganda yopiladi (Wait uchun)
+}
+
+// MarshalJSON - task JSON ga chipta IDsisiz chiqadi
+// Tasklar ommaviy endpointlarda ko'rinadi, chipta IDsi esa /t/:ticket_id sahifasining kaliti
+func (t AudioTask) MarshalJSON() ([]byte, error) {
+	type plain AudioTask
+	view := plain(t)
+	view.TicketID = ""
+	return json.Marshal(view)
+}
+
+// IsBroadcast - umumiy xabarmi (chaqiriqlardan oldin ijro etiladi)
+func (t AudioTask) IsBroadcast() bool {
+	return t.Kind == TaskKindBroadcast || t.Kind == TaskKindSchedule
+}
+
+// Finished - task yakuniy holatdami
+func (t AudioTask) Finished() bool {
+	return t.State != TaskQueued && t.State != TaskPlaying
+}
+
+// TaskStats - boshlanganidan beri tasklar natijalari
+type TaskStats struct {
+	Played    uint64 `json:"played"`
+	Partial   uint64 `json:"partial"`
+	Failed    uint64 `json:"failed"`
+	Dropped   uint64 `json:"dropped"`
+	Cancelled uint64 `json:"cancelled"`
 }
 
 // 🚀 AUDIO QUEUE SERVICE
+// Kutayotgan tasklar tartibli ro'yxatda: ularni ko'rish, bekor qilish
+// va oldinga o'tkazish mumkin
 type AudioQueueService struct {
 	audioService *AudioService
-	tasks        chan AudioTask
+	zone         string // Zona nomi (bitta zonali sozlamada "default")
+	sink         Sink   // Zona chiqishi, nil - audioService chiqishi
 	workerCount  int
+	capacity     int
+	dedupWindow  time.Duration
 	wg           sync.WaitGroup
 	isRunning    bool
-	mu           sync.RWMutex
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	pending   []*AudioTask
+	playing   map[int]*AudioTask    // Worker -> hozir ijro etilayotgan task
+	preempted map[string]bool       // Xabar uchun to'xtatilgan tasklar (qayta navbatga qo'yiladi)
+	recent    map[string]*AudioTask // Takrorlanish kaliti -> oxirgi qo'shilgan task
+	tasks     map[string]*AudioTask // ID -> task (kutayotgan, ijrodagi va oxirgi tugaganlar)
+	finished  []string              // Tugagan tasklar tartibi (eskilarini o'chirish uchun)
+	stats     TaskStats
+	history   *History // Tugagan tasklar shu yerga yoziladi, nil - yozilmaydi
 }
 
-func NewAudioQueueService(audioService *AudioService, workerCount int) *AudioQueueService {
-	return &AudioQueueService{
+// dedupWindow <= 0 bo'lsa takroriy e'lonlar birlashtirilmaydi
+func NewAudioQueueService(audioService *AudioService, workerCount int, dedupWindow time.Duration) *AudioQueueService {
+	q := &AudioQueueService{
 		audioService: audioService,
-		tasks:        make(chan AudioTask, 100), // 100 ta task uchun buffer
 		workerCount:  workerCount,
+		capacity:     DefaultQueueCapacity,
+		dedupWindow:  dedupWindow,
 		isRunning:    false,
+		playing:      make(map[int]*AudioTask),
+		preempted:    make(map[string]bool),
+		recent:       make(map[string]*AudioTask),
+		tasks:        make(map[string]*AudioTask),
 	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// WithHistory - tugagan tasklarni tarixga yozadi
+func (q *AudioQueueService) WithHistory(history *History) *AudioQueueService {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.history = history
+	return q
+}
+
+// WithZone - navbatni zonaga bog'laydi: tasklar shu nom bilan belgilanadi va
+// sink orqali ijro etiladi (nil - audioService chiqishi)
+func (q *AudioQueueService) WithZone(name string, sink Sink) *AudioQueueService {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.zone = name
+	q.sink = sink
+	return q
+}
+
+// Zone - zona nomi
+func (q *AudioQueueService) Zone() string {
+	return q.zone
+}
+
+// output - e'lonlar chiqariladigan sink
+func (q *AudioQueueService) output() Sink {
+	if q.sink != nil {
+		return q.sink
+	}
+	return q.audioService.sink
+}
+
+// DeviceState - zona chiqish qurilmasi holati
+func (q *AudioQueueService) DeviceState() DeviceState {
+	return deviceState(q.output())
+}
+
+// History - e'lonlar tarixi (sozlanmagan bo'lsa nil)
+func (q *AudioQueueService) History() *History {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.history
 }
 
 // 🎯 QUEUE NI ISHGA TUSHIRISH
@@ -50,41 +197,159 @@ func (q *AudioQueueService) Start() {
 		go q.worker(i + 1)
 	}
 
-	log.Printf("🚀 Audio Queue Service started with %d workers", q.workerCount)
+	log.Printf("🚀 Audio Queue Service started with %d workers (zona: %s)", q.workerCount, q.zone)
 }
 
 // 🛑 QUEUE NI TO'XTATISH
 func (q *AudioQueueService) Stop() {
 	q.mu.Lock()
-	defer q.mu.Unlock()
-
 	if !q.isRunning {
+		q.mu.Unlock()
 		return
 	}
-
 	q.isRunning = false
-	close(q.tasks)
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
 	q.wg.Wait()
 
 	log.Println("🛑 Audio Queue Service stopped")
 }
 
 // 📥 TASK QO'SHISH
-func (q *AudioQueueService) AddTask(queueNumber, roomNumber string) {
-	task := AudioTask{
-		QueueNumber: queueNumber,
-		RoomNumber:  roomNumber,
-		Timestamp:   time.Now(),
-		Priority:    2, // Default priority
+// Noto'g'ri navbat raqami navbatga qo'yilmaydi.
+// Shu chipta dedupWindow ichida qo'shilgan va hali navbatda kutayotgan bo'lsa,
+// yangi task yaratilmaydi - mavjud task qaytariladi.
+// Navbat to'la bo'lsa, "dropped" holatidagi task va ErrQueueFull qaytariladi
+func (q *AudioQueueService) AddTask(req Announcement) (AudioTask, error) {
+	return q.enqueue(req, TaskKindCall)
+}
+
+// Repeat - e'lonni qayta navbatga qo'yadi (takroriylik oynasi qo'llanmaydi)
+// Xuddi shu e'lon hali navbatda kutayotgan bo'lsa, o'sha task qaytariladi
+func (q *AudioQueueService) Repeat(req Announcement) (AudioTask, error) {
+	return q.enqueue(req, TaskKindRepeat)
+}
+
+func (q *AudioQueueService) enqueue(req Announcement, kind string) (AudioTask, error) {
+	if _, err := ParseQueueNumber(req.QueueNumber); err != nil {
+		log.Printf("❌ Task qo'shilmadi: %v", err)
+		return AudioTask{}, err
 	}
 
-	select {
-	case q.tasks <- task:
-		log.Printf("📥 Audio task qo'shildi: %s -> %s (navbat: %d)",
-			queueNumber, roomNumber, len(q.tasks))
-	default:
-		log.Printf("❌ Navbat to'la! Task qo'shilmadi: %s", queueNumber)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	key := dedupKey(req)
+	if kind == TaskKindRepeat {
+		for _, task := range q.pending {
+			if dedupKey(task.Announcement) == key {
+				return *task, nil
+			}
+		}
+	} else if q.dedupWindow > 0 {
+		q.pruneRecentLocked(now)
+		// Faqat hali kutayotgan task bilan birlashtiriladi: aytib bo'lingan
+		// chipta qayta chaqirilsa (masalan, recall) yana aytilishi kerak
+		if task, ok := q.recent[key]; ok && task.State == TaskQueued {
+			log.Printf("🔁 Takroriy e'lon birlashtirildi: %s -> %s", req.QueueNumber, req.RoomNumber)
+			return *task, nil
+		}
+	}
+
+	task := &AudioTask{
+		ID:           newTaskID(),
+		Announcement: req,
+		Kind:         kind,
+		Zone:         q.zone,
+		Timestamp:    now,
+		Priority:     2, // Default priority
+		State:        TaskQueued,
+		done:         make(chan struct{}),
 	}
+	q.tasks[task.ID] = task
+
+	if len(q.pending) >= q.capacity {
+		log.Printf("❌ Navbat to'la! Task qo'shilmadi: %s", req.QueueNumber)
+		task.Error = ErrQueueFull.Error()
+		q.finishLocked(task, TaskDropped)
+		return *task, ErrQueueFull
+	}
+
+	q.pending = append(q.pending, task)
+	if kind == TaskKindCall {
+		q.recent[key] = task
+	}
+	q.cond.Signal()
+
+	log.Printf("📥 Audio task qo'shildi: %s -> %s (navbat: %d, %s, zona: %s)",
+		req.QueueNumber, req.RoomNumber, len(q.pending), kind, q.zone)
+	return *task, nil
+}
+
+// Broadcast - umumiy xabarni navbat boshiga qo'yadi (oldingi xabarlardan keyin)
+// preempt bo'lsa, hozir ijro etilayotgan chaqiriq to'xtatiladi va xabardan
+// keyin qaytadan aytiladi
+func (q *AudioQueueService) Broadcast(items []string, preempt bool) (AudioTask, error) {
+	return q.broadcast(items, preempt, "")
+}
+
+// Scheduled - jadval bo'yicha xabar: umumiy xabar kabi, tarixda jadval ID si bilan
+func (q *AudioQueueService) Scheduled(scheduleID string, items []string, preempt bool) (AudioTask, error) {
+	return q.broadcast(items, preempt, scheduleID)
+}
+
+func (q *AudioQueueService) broadcast(items []string, preempt bool, scheduleID string) (AudioTask, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	kind := TaskKindBroadcast
+	if scheduleID != "" {
+		kind = TaskKindSchedule
+	}
+	task := &AudioTask{
+		ID:         newTaskID(),
+		Kind:       kind,
+		Items:      items,
+		ScheduleID: scheduleID,
+		Zone:       q.zone,
+		Timestamp:  time.Now(),
+		Priority:   1,
+		State:      TaskQueued,
+		done:       make(chan struct{}),
+	}
+	q.tasks[task.ID] = task
+
+	// Xabar navbat hajmiga qaramay qo'shiladi - favqulodda xabar tushib qolmasligi kerak
+	q.insertAfterBroadcastsLocked(task)
+	q.cond.Signal()
+	log.Printf("📣 Umumiy xabar navbat boshiga qo'yildi: %v (preempt: %v)", items, preempt)
+
+	if preempt {
+		interrupted := false
+		for _, playing := range q.playing {
+			if !playing.IsBroadcast() {
+				q.preempted[playing.ID] = true
+				interrupted = true
+			}
+		}
+		if interrupted {
+			q.output().Interrupt()
+		}
+	}
+	return *task, nil
+}
+
+// insertAfterBroadcastsLocked - taskni kutayotgan xabarlardan keyin, chaqiriqlardan oldin qo'yadi
+func (q *AudioQueueService) insertAfterBroadcastsLocked(task *AudioTask) {
+	i := 0
+	for i < len(q.pending) && q.pending[i].IsBroadcast() {
+		i++
+	}
+	q.pending = append(q.pending, nil)
+	copy(q.pending[i+1:], q.pending[i:])
+	q.pending[i] = task
 }
 
 // 👷 WORKER FUNCTION
@@ -93,21 +358,53 @@ func (q *AudioQueueService) worker(id int) {
 
 	log.Printf("👷 Worker %d ishga tushdi", id)
 
-	for task := range q.tasks {
-		if !q.isRunning {
+	for {
+		task, ok := q.next(id)
+		if !ok {
 			break
 		}
 
 		log.Printf("🎯 Worker %d task bajarayapti: %s -> %s (qolgan: %d)",
-			id, task.QueueNumber, task.RoomNumber, len(q.tasks))
+			id, task.QueueNumber, task.RoomNumber, q.Len())
 
 		// Audio ni ijro etish
-		if err := q.audioService.PlayAnnouncement(task.QueueNumber, task.RoomNumber); err != nil {
-			log.Printf("❌ Worker %d xato: %v", id, err)
+		var steps []StepResult
+		var route playRoute
+		var err error
+		if task.IsBroadcast() {
+			steps, route, err = q.audioService.playBroadcast(q.output(), task.Items)
 		} else {
+			steps, route, err = q.audioService.playAnnouncement(q.output(), task.Announcement)
+		}
+
+		// Xabar uchun to'xtatilgan chaqiriq xabardan keyin qaytadan aytiladi
+		if q.requeuePreempted(id, task, err) {
+			continue
+		}
+
+		state := TaskPlayed
+		switch {
+		case err != nil:
+			state = TaskFailed
+			log.Printf("❌ Worker %d xato: %v", id, err)
+		case playedSteps(steps) < len(steps):
+			state = TaskPartial
+			log.Printf("⚠️ Worker %d task qisman ijro etildi: %s", id, task.QueueNumber)
+		default:
 			log.Printf("✅ Worker %d task tugatti: %s", id, task.QueueNumber)
 		}
 
+		q.mu.Lock()
+		delete(q.playing, id)
+		task.Steps = steps
+		task.Sink = route.sink
+		task.Fallback = route.fallback
+		if err != nil {
+			task.Error = err.Error()
+		}
+		q.finishLocked(task, state)
+		q.mu.Unlock()
+
 		// Keyingi task dan oldin qisqa pauza
 		time.Sleep(100 * time.Millisecond)
 	}
@@ -115,16 +412,234 @@ func (q *AudioQueueService) worker(id int) {
 	log.Printf("👷 Worker %d to'xtadi", id)
 }
 
+// next - navbatdagi birinchi taskni oladi (bo'lmasa kutadi)
+// Servis to'xtatilganda false qaytaradi
+func (q *AudioQueueService) next(worker int) (*AudioTask, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.isRunning && len(q.pending) == 0 {
+		q.cond.Wait()
+	}
+	if !q.isRunning {
+		return nil, false
+	}
+
+	task := q.pending[0]
+	q.pending = q.pending[1:]
+	q.playing[worker] = task
+	now := time.Now()
+	task.State = TaskPlaying
+	task.StartedAt = &now
+	return task, true
+}
+
+// requeuePreempted - to'xtatilgan taskni xabarlardan keyin navbatga qaytaradi
+func (q *AudioQueueService) requeuePreempted(worker int, task *AudioTask, err error) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	preempted := q.preempted[task.ID]
+	delete(q.preempted, task.ID)
+	if !preempted || !errors.Is(err, ErrInterrupted) {
+		return false
+	}
+
+	delete(q.playing, worker)
+	task.State = TaskQueued
+	task.StartedAt = nil
+	q.insertAfterBroadcastsLocked(task)
+	q.cond.Signal()
+	log.Printf("⏸️ %s xabar uchun to'xtatildi, keyin qayta aytiladi", task.QueueNumber)
+	return true
+}
+
+// finishLocked - taskni yakuniy holatga o'tkazadi va kutayotganlarni uyg'otadi
+func (q *AudioQueueService) finishLocked(task *AudioTask, state string) {
+	now := time.Now()
+	task.State = state
+	task.FinishedAt = &now
+	close(task.done)
+
+	switch state {
+	case TaskPlayed:
+		q.stats.Played++
+	case TaskPartial:
+		q.stats.Partial++
+	case TaskFailed:
+		q.stats.Failed++
+	case TaskDropped:
+		q.stats.Dropped++
+	case TaskCancelled:
+		q.stats.Cancelled++
+	}
+
+	if q.history != nil {
+		q.history.Add(HistoryEntry{
+			TaskID:       task.ID,
+			Announcement: task.Announcement,
+			Kind:         task.Kind,
+			Items:        task.Items,
+			ScheduleID:   task.ScheduleID,
+			Zone:         task.Zone,
+			State:        task.State,
+			Error:        task.Error,
+			Steps:        task.Steps,
+			Sink:         task.Sink,
+			Fallback:     task.Fallback,
+			QueuedAt:     task.Timestamp,
+			StartedAt:    task.StartedAt,
+			FinishedAt:   now,
+		})
+	}
+
+	q.finished = append(q.finished, task.ID)
+	for len(q.finished) > DefaultTaskHistory {
+		delete(q.tasks, q.finished[0])
+		q.finished = q.finished[1:]
+	}
+}
+
+// Task - task holati ID bo'yicha
+func (q *AudioQueueService) Task(id string) (AudioTask, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	task, ok := q.tasks[id]
+	if !ok {
+		return AudioTask{}, false
+	}
+	return *task, true
+}
+
+// Wait - task tugashini kutadi (yoki ctx bekor bo'lguncha)
+// ctx tugasa, taskning joriy holati va ctx xatosi qaytariladi
+func (q *AudioQueueService) Wait(ctx context.Context, id string) (AudioTask, error) {
+	q.mu.Lock()
+	task, ok := q.tasks[id]
+	q.mu.Unlock()
+	if !ok {
+		return AudioTask{}, ErrTaskNotFound
+	}
+
+	select {
+	case <-task.done:
+	case <-ctx.Done():
+		current, _ := q.Task(id)
+		return current, ctx.Err()
+	}
+
+	current, _ := q.Task(id)
+	return current, nil
+}
+
+// Stats - tasklar natijalari statistikasi
+func (q *AudioQueueService) Stats() TaskStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.stats
+}
+
+// Len - kutayotgan tasklar soni
+func (q *AudioQueueService) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Pending - kutayotgan tasklar, ijro tartibida
+func (q *AudioQueueService) Pending() []AudioTask {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	tasks := make([]AudioTask, 0, len(q.pending))
+	for _, task := range q.pending {
+		tasks = append(tasks, *task)
+	}
+	return tasks
+}
+
+// Position - taskning navbatdagi o'rni (1 - keyingi), kutmayotgan bo'lsa 0
+func (q *AudioQueueService) Position(id string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, task := range q.pending {
+		if task.ID == id {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Current - hozir ijro etilayotgan tasklar (har bir worker uchun bittadan)
+func (q *AudioQueueService) Current() []AudioTask {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	tasks := make([]AudioTask, 0, len(q.playing))
+	for _, task := range q.playing {
+		tasks = append(tasks, *task)
+	}
+	return tasks
+}
+
+// Cancel - kutayotgan taskni navbatdan olib tashlaydi
+// Ijro boshlangan task bekor qilinmaydi
+func (q *AudioQueueService) Cancel(id string) (AudioTask, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, task := range q.pending {
+		if task.ID == id {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			// Bekor qilingan chipta qayta chaqirilsa, birlashtirilmasin
+			delete(q.recent, dedupKey(task.Announcement))
+			q.finishLocked(task, TaskCancelled)
+			log.Printf("🗑️ Task bekor qilindi: %s (%s)", task.QueueNumber, task.ID)
+			return *task, true
+		}
+	}
+	return AudioTask{}, false
+}
+
+// MoveToFront - kutayotgan taskni navbat boshiga o'tkazadi
+// Chaqiriq kutayotgan umumiy xabarlardan oldinga o'tmaydi (favqulodda xabar birinchi)
+func (q *AudioQueueService) MoveToFront(id string) (AudioTask, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, task := range q.pending {
+		if task.ID == id {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			if task.IsBroadcast() {
+				q.pending = append([]*AudioTask{task}, q.pending...)
+			} else {
+				q.insertAfterBroadcastsLocked(task)
+			}
+			log.Printf("⏫ Task navbat boshiga o'tkazildi: %s (%s)", task.QueueNumber, task.ID)
+			return *task, true
+		}
+	}
+	return AudioTask{}, false
+}
+
 // 📊 QUEUE STATUS
 func (q *AudioQueueService) GetStatus() map[string]interface{} {
-	q.mu.RLock()
-	defer q.mu.RUnlock()
+	q.mu.Lock()
+	defer q.mu.Unlock()
 
 	return map[string]interface{}{
+		"zone":         q.zone,
+		"sink":         q.output().Name(),
+		"device":       q.DeviceState(),
 		"is_running":   q.isRunning,
-		"queue_length": len(q.tasks),
+		"queue_length": len(q.pending),
+		"playing":      len(q.playing),
 		"worker_count": q.workerCount,
-		"buffer_size":  cap(q.tasks),
+		"buffer_size":  q.capacity,
+		"dedup_window": q.dedupWindow.String(),
+		"tasks":        q.stats,
 	}
 }
 
@@ -133,14 +648,37 @@ func (q *AudioQueueService) ClearQueue() int {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	count := 0
-	for {
-		select {
-		case <-q.tasks:
-			count++
-		default:
-			log.Printf("🗑️ Navbat tozalandi: %d task o'chirildi", count)
-			return count
+	count := len(q.pending)
+	for _, task := range q.pending {
+		delete(q.recent, dedupKey(task.Announcement))
+		q.finishLocked(task, TaskCancelled)
+	}
+	q.pending = nil
+	log.Printf("🗑️ Navbat tozalandi: %d task o'chirildi", count)
+	return count
+}
+
+// dedupKey - bir xil chaqiriqni aniqlash: chipta, bo'lmasa navbat raqami va xona
+func dedupKey(req Announcement) string {
+	if req.TicketID != "" {
+		return "ticket:" + req.TicketID
+	}
+	return fmt.Sprintf("call:%s|%s", req.QueueNumber, req.RoomNumber)
+}
+
+func (q *AudioQueueService) pruneRecentLocked(now time.Time) {
+	for key, task := range q.recent {
+		if now.Sub(task.Timestamp) >= q.dedupWindow {
+			delete(q.recent, key)
 		}
 	}
 }
+
+// newTaskID - qisqa tasodifiy identifikator
+func newTaskID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,54 @@
+package audio
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTaskJSONHidesTicketID(t *testing.T) {
+	task := AudioTask{
+		ID:           "t1",
+		Announcement: Announcement{TicketID: "maxfiy-chipta", QueueNumber: "A005", RoomNumber: "3"},
+		Kind:         TaskKindCall,
+		State:        TaskQueued,
+	}
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "maxfiy-chipta") || strings.Contains(string(data), "ticket_id") {
+		t.Fatalf("task JSON da chipta IDsi bor: %s", data)
+	}
+	if !strings.Contains(string(data), `"queue_number":"A005"`) {
+		t.Fatalf("task JSON da navbat raqami yo'q: %s", data)
+	}
+	if task.TicketID != "maxfiy-chipta" {
+		t.Fatal("MarshalJSON taskning o'zini o'zgartirmasligi kerak")
+	}
+}
+
+func TestQueuePosition(t *testing.T) {
+	memory := NewMemorySink()
+	service := newTestService(t, writeTestPack(t, testCallClips), memory)
+	queue := NewAudioQueueService(service, 1, 0).WithZone("test", memory)
+
+	first, err := queue.AddTask(Announcement{QueueNumber: "5", RoomNumber: "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := queue.AddTask(Announcement{QueueNumber: "7", RoomNumber: "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := queue.Position(first.ID); got != 1 {
+		t.Errorf("birinchi task o'rni = %d, kutilgan 1", got)
+	}
+	if got := queue.Position(second.ID); got != 2 {
+		t.Errorf("ikkinchi task o'rni = %d, kutilgan 2", got)
+	}
+	if got := queue.Position("yo'q"); got != 0 {
+		t.Errorf("navbatda yo'q task o'rni = %d, kutilgan 0", got)
+	}
+}
@@ -0,0 +1,108 @@
+package audio
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/faiface/beep"
+)
+
+// ==============================
+// E'LONLARNI WAV FAYLGA RENDER QILISH
+// Zal ekranidagi brauzer (televizor karnaylari) e'lonni shu fayldan ijro etadi.
+// Opus o'rniga WAV: qo'shimcha kodek kutubxonasisiz barcha brauzerlarda ishlaydi.
+// ==============================
+
+// DefaultRenderKeep - diskda saqlanadigan oxirgi e'lonlar soni
+const DefaultRenderKeep = 200
+
+// renderIDPattern - faqat o'zimiz yaratgan ID lar qabul qilinadi (yo'l bilan o'ynashning oldini olish)
+var renderIDPattern = regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+// AnnouncementStore - render qilingan e'lonlar papkasi
+// Eski fayllar keep chegarasidan oshganda o'chiriladi
+type AnnouncementStore struct {
+	dir  string
+	keep int
+	mu   sync.Mutex
+}
+
+func NewAnnouncementStore(dir string, keep int) (*AnnouncementStore, error) {
+	if keep <= 0 {
+		keep = DefaultRenderKeep
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("e'lonlar papkasi yaratilmadi: %w", err)
+	}
+	return &AnnouncementStore{dir: dir, keep: keep}, nil
+}
+
+// Save - oqimni yangi WAV faylga yozadi va uning ID sini qaytaradi
+func (s *AnnouncementStore) Save(stream beep.Streamer, format beep.Format) (string, error) {
+	id, err := newRenderID()
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := writeWAV(filepath.Join(s.dir, id+".wav"), stream, format); err != nil {
+		return "", err
+	}
+	s.pruneLocked()
+	return id, nil
+}
+
+// Path - ID bo'yicha fayl yo'li (fayl mavjud bo'lishi shart)
+func (s *AnnouncementStore) Path(id string) (string, error) {
+	if !renderIDPattern.MatchString(id) {
+		return "", os.ErrNotExist
+	}
+	path := filepath.Join(s.dir, id+".wav")
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// pruneLocked - eng eski fayllarni o'chiradi
+func (s *AnnouncementStore) pruneLocked() {
+	matches, err := filepath.Glob(filepath.Join(s.dir, "*.wav"))
+	if err != nil || len(matches) <= s.keep {
+		return
+	}
+
+	type file struct {
+		path string
+		mod  int64
+	}
+	files := make([]file, 0, len(matches))
+	for _, path := range matches {
+		if info, err := os.Stat(path); err == nil {
+			files = append(files, file{path, info.ModTime().UnixNano()})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].mod < files[j].mod })
+
+	for _, f := range files[:max(len(files)-s.keep, 0)] {
+		if err := os.Remove(f.path); err != nil {
+			log.Printf("⚠️ Eski e'lon o'chirilmadi: %s (%v)", f.path, err)
+		}
+	}
+}
+
+func newRenderID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("id yaratilmadi: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,65 @@
+package audio
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// roomWordClip - alohida yozilgan "xona" so'zi (paketda bo'lmasligi mumkin)
+const roomWordClip = "words/xona"
+
+// roomCodePattern - "204", "B-204", "B204", "204A", "B 204-xona"
+var roomCodePattern = regexp.MustCompile(`^([A-Za-z]*)[-\s]?(\d+)[-\s]?([A-Za-z]*)$`)
+
+// RoomClips - xona raqamini klip tokenlariga aylantiradi
+// Yozib olingan "numbers/<xona>-xona" bo'lsa, u ishlatiladi.
+// Aks holda: bino harfi + raqam (NumberClips) + xona harfi, "xona" so'zi
+// yozilgan bo'lsa oxirida: "B-204" -> "letters/b", "numbers/200", "numbers/4" [, "words/xona"].
+// So'z yozilmagan bo'lsa qo'shilmaydi - standart shablonda xonadan keyin
+// "phrases/honaga_kelishin" ("...xonaga kelishingiz") aytiladi
+func RoomClips(room string, has func(token string) bool) ([]string, error) {
+	room = strings.TrimSuffix(strings.TrimSpace(room), "-xona")
+	if room == "" {
+		return nil, fmt.Errorf("xona raqami bo'sh")
+	}
+
+	recorded := fmt.Sprintf("numbers/%s-xona", room)
+	if has != nil && has(recorded) {
+		return []string{recorded}, nil
+	}
+
+	match := roomCodePattern.FindStringSubmatch(room)
+	if match == nil {
+		return nil, fmt.Errorf("xona raqamini o'qib bo'lmadi: %q", room)
+	}
+
+	number, err := strconv.Atoi(match[2])
+	if err != nil {
+		return nil, fmt.Errorf("xona raqamini o'qib bo'lmadi: %q", room)
+	}
+
+	var clips []string
+	clips = append(clips, letterClips(match[1])...)
+	numberClips, err := NumberClips(number, has)
+	if err != nil {
+		return nil, err
+	}
+	clips = append(clips, numberClips...)
+	clips = append(clips, letterClips(match[3])...)
+	if has != nil && has(roomWordClip) {
+		clips = append(clips, roomWordClip)
+	}
+
+	return clips, nil
+}
+
+// letterClips - harflarni alohida kliplarga ajratadi: "AB" -> "letters/a", "letters/b"
+func letterClips(letters string) []string {
+	clips := make([]string, 0, len(letters))
+	for _, letter := range strings.ToLower(letters) {
+		clips = append(clips, fmt.Sprintf("letters/%c", letter))
+	}
+	return clips
+}
@@ -0,0 +1,44 @@
+package audio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoomClips(t *testing.T) {
+	recorded := func(token string) bool { return token == "numbers/301-xona" }
+	hundreds := func(token string) bool { return token == "numbers/200" }
+	withWord := func(token string) bool { return token == "numbers/200" || token == roomWordClip }
+
+	tests := []struct {
+		room string
+		has  func(token string) bool
+		want []string
+	}{
+		{"301", recorded, []string{"numbers/301-xona"}},
+		{"301-xona", recorded, []string{"numbers/301-xona"}},
+		{"204", hundreds, []string{"numbers/200", "numbers/4"}},
+		{"204", nil, []string{"numbers/2", "numbers/100", "numbers/4"}},
+		{"204", withWord, []string{"numbers/200", "numbers/4", roomWordClip}},
+		{"B-204", hundreds, []string{"letters/b", "numbers/200", "numbers/4"}},
+		{"15A", nil, []string{"numbers/10", "numbers/5", "letters/a"}},
+	}
+
+	for _, tt := range tests {
+		got, err := RoomClips(tt.room, tt.has)
+		if err != nil {
+			t.Fatalf("RoomClips(%q): %v", tt.room, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("RoomClips(%q) = %v, kutilgan %v", tt.room, got, tt.want)
+		}
+	}
+}
+
+func TestRoomClipsInvalid(t *testing.T) {
+	for _, room := range []string{"", "xona", "2-04-1"} {
+		if clips, err := RoomClips(room, nil); err == nil {
+			t.Errorf("RoomClips(%q) = %v, xato kutilgan", room, clips)
+		}
+	}
+}
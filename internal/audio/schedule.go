@@ -0,0 +1,489 @@
+package audio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==============================
+// JADVAL BO'YICHA XABARLAR
+// Har kuni takrorlanadigan xabarlar (13:00 tushlik, 17:30 yopilish) cron
+// ifodasi bo'yicha umumiy xabar sifatida navbatga qo'yiladi.
+// Jadvallar va bayram kunlari API orqali o'zgartiriladi va data papkasida
+// saqlanadi. Server o'chiq paytda o'tib ketgan xabarlar keyin aytilmaydi.
+// ==============================
+
+// Bayram kunlaridagi qoida
+const (
+	HolidaySkip = "skip" // Bayram kunlari aytilmaydi (standart)
+	HolidayOnly = "only" // Faqat bayram kunlari
+	HolidayAny  = "any"  // Bayramga qaramay
+)
+
+// ErrScheduleNotFound - jadval topilmadi
+var ErrScheduleNotFound = errors.New("jadval topilmadi")
+
+// maxCatchUp - server "uxlab" qolganda (kompyuter sekinlashsa) tekshiriladigan o'tgan daqiqalar
+const maxCatchUp = 5 * time.Minute
+
+// Schedule - takrorlanadigan xabar
+type Schedule struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`                 // "Tushlik tanaffusi"
+	Cron      string     `json:"cron"`                 // "0 13 * * 1-5"
+	MessageID string     `json:"message_id,omitempty"` // audio.messages dagi xabar
+	Items     []string   `json:"items,omitempty"`      // yoki tayyor tokenlar
+	Preempt   bool       `json:"preempt"`              // Joriy e'lonni to'xtatish
+	Zones     []string   `json:"zones,omitempty"`      // Bo'sh - barcha zonalar
+	Holidays  string     `json:"holidays"`             // skip, only, any
+	Enabled   bool       `json:"enabled"`
+	LastRun   *time.Time `json:"last_run,omitempty"`
+	NextRun   *time.Time `json:"next_run,omitempty"` // Faqat API javobida, saqlanmaydi
+}
+
+// scheduleState - diskka yoziladigan jadvallar va bayram kunlari
+type scheduleState struct {
+	Schedules []*Schedule `json:"schedules"`
+	Holidays  []string    `json:"holidays"` // "2026-03-21" yoki har yili "03-21"
+}
+
+// Scheduler - jadvallarni saqlaydi va vaqti kelganda navbatga qo'yadi
+type Scheduler struct {
+	zones *Zones
+	path  string
+
+	mu        sync.Mutex
+	schedules []*Schedule
+	holidays  []string
+	specs     map[string]cronSpec // ID -> tahlil qilingan cron
+	lastTick  time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewScheduler - jadvallarni fayldan yuklaydi; path bo'sh bo'lsa faqat xotirada
+func NewScheduler(zones *Zones, path string) *Scheduler {
+	s := &Scheduler{
+		zones: zones,
+		path:  path,
+		specs: map[string]cronSpec{},
+	}
+	if err := s.load(); err != nil {
+		log.Printf("⚠️ Xabarlar jadvali: %v (bo'sh jadval bilan boshlanadi)", err)
+	}
+	return s
+}
+
+// Start - har daqiqa boshida jadvallarni tekshiradi
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.lastTick = time.Now().Truncate(time.Minute)
+
+	s.wg.Add(1)
+	go s.loop(s.stop)
+	log.Printf("🗓️ Xabarlar jadvali ishga tushdi (%d ta jadval)", len(s.schedules))
+}
+
+// Stop - jadval tekshiruvini to'xtatadi
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if s.stop == nil {
+		s.mu.Unlock()
+		return
+	}
+	close(s.stop)
+	s.stop = nil
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(stop chan struct{}) {
+	defer s.wg.Done()
+	for {
+		now := time.Now()
+		wait := now.Truncate(time.Minute).Add(time.Minute).Sub(now)
+		select {
+		case <-stop:
+			return
+		case <-time.After(wait):
+		}
+		s.tick(time.Now())
+	}
+}
+
+// tick - oxirgi tekshiruvdan keyingi har bir daqiqa uchun mos jadvallarni ishga tushiradi
+func (s *Scheduler) tick(now time.Time) {
+	now = now.Truncate(time.Minute)
+
+	s.mu.Lock()
+	from := s.lastTick.Add(time.Minute)
+	if now.Sub(from) > maxCatchUp {
+		from = now.Add(-maxCatchUp)
+	}
+	s.lastTick = now
+
+	// O'tkazib yuborilgan daqiqalar ko'p bo'lsa ham, jadval bir marta aytiladi
+	var runs []Schedule
+	for _, schedule := range s.schedules {
+		for minute := from; !minute.After(now); minute = minute.Add(time.Minute) {
+			if s.dueLocked(schedule, minute) {
+				runAt := minute
+				schedule.LastRun = &runAt
+				runs = append(runs, *schedule)
+				break
+			}
+		}
+	}
+	if len(runs) > 0 {
+		s.saveLocked()
+	}
+	s.mu.Unlock()
+
+	for _, schedule := range runs {
+		s.run(schedule)
+	}
+}
+
+// dueLocked - jadval shu daqiqada aytilishi kerakmi (bir daqiqada bir marta)
+func (s *Scheduler) dueLocked(schedule *Schedule, minute time.Time) bool {
+	if !schedule.Enabled {
+		return false
+	}
+	if schedule.LastRun != nil && !schedule.LastRun.Before(minute) {
+		return false
+	}
+	spec, ok := s.specs[schedule.ID]
+	return ok && spec.matches(minute) && s.holidayAllowsLocked(schedule, minute)
+}
+
+// holidayAllowsLocked - bayram qoidasi shu kunga ruxsat beradimi
+func (s *Scheduler) holidayAllowsLocked(schedule *Schedule, day time.Time) bool {
+	holiday := s.isHolidayLocked(day)
+	switch schedule.Holidays {
+	case HolidayOnly:
+		return holiday
+	case HolidayAny:
+		return true
+	default:
+		return !holiday
+	}
+}
+
+func (s *Scheduler) isHolidayLocked(day time.Time) bool {
+	date := day.Format("2006-01-02")
+	yearly := day.Format("01-02")
+	for _, holiday := range s.holidays {
+		if holiday == date || holiday == yearly {
+			return true
+		}
+	}
+	return false
+}
+
+// run - xabarni navbatga qo'yadi; xabar aniqlanmasa yoki navbatga qo'yilmasa,
+// tarixga xato sifatida yoziladi
+func (s *Scheduler) run(schedule Schedule) {
+	items, err := s.zones.audioService.ResolveBroadcast(Broadcast{MessageID: schedule.MessageID, Items: schedule.Items})
+	if err != nil {
+		log.Printf("❌ Jadval %q ishga tushmadi: %v", schedule.Name, err)
+		s.recordFailure(schedule, schedule.Items, err)
+		return
+	}
+
+	log.Printf("🗓️ Jadval bo'yicha xabar: %s (%s)", schedule.Name, schedule.Cron)
+	if _, err := s.zones.Scheduled(schedule.Zones, schedule.ID, items, schedule.Preempt); err != nil {
+		// Masalan, jadvaldagi zona sozlamalardan olib tashlangan
+		log.Printf("❌ Jadval %q navbatga qo'yilmadi: %v", schedule.Name, err)
+		s.recordFailure(schedule, items, err)
+	}
+}
+
+// recordFailure - aytilmay qolgan jadval xabarini tarixga yozadi
+func (s *Scheduler) recordFailure(schedule Schedule, items []string, err error) {
+	history := s.zones.History()
+	if history == nil {
+		return
+	}
+	now := time.Now()
+	history.Add(HistoryEntry{
+		TaskID:     newTaskID(),
+		Kind:       TaskKindSchedule,
+		Items:      items,
+		ScheduleID: schedule.ID,
+		State:      TaskFailed,
+		Error:      err.Error(),
+		QueuedAt:   now,
+		FinishedAt: now,
+	})
+}
+
+// RunNow - jadvaldagi xabarni hozir navbatga qo'yadi (sinash uchun, last_run o'zgarmaydi)
+// Har bir zona uchun bittadan task qaytariladi
+func (s *Scheduler) RunNow(id string) ([]AudioTask, error) {
+	schedule, ok := s.Get(id)
+	if !ok {
+		return nil, ErrScheduleNotFound
+	}
+	items, err := s.zones.audioService.ResolveBroadcast(Broadcast{MessageID: schedule.MessageID, Items: schedule.Items})
+	if err != nil {
+		return nil, err
+	}
+	return s.zones.Scheduled(schedule.Zones, schedule.ID, items, schedule.Preempt)
+}
+
+// List - barcha jadvallar, keyingi ijro vaqti bilan
+func (s *Scheduler) List() []Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	result := make([]Schedule, 0, len(s.schedules))
+	for _, schedule := range s.schedules {
+		result = append(result, s.viewLocked(schedule, now))
+	}
+	return result
+}
+
+// Get - jadval ID bo'yicha
+func (s *Scheduler) Get(id string) (Schedule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, schedule := range s.schedules {
+		if schedule.ID == id {
+			return s.viewLocked(schedule, time.Now()), true
+		}
+	}
+	return Schedule{}, false
+}
+
+// Create - yangi jadval qo'shadi
+func (s *Scheduler) Create(schedule Schedule) (Schedule, error) {
+	spec, err := s.validate(&schedule)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule.ID = newTaskID()
+	schedule.LastRun = nil
+	schedule.NextRun = nil
+	s.schedules = append(s.schedules, &schedule)
+	s.specs[schedule.ID] = spec
+	s.saveLocked()
+
+	log.Printf("🗓️ Jadval qo'shildi: %s (%s)", schedule.Name, schedule.Cron)
+	return s.viewLocked(&schedule, time.Now()), nil
+}
+
+// Update - jadvalni almashtiradi (ID va last_run saqlanadi)
+func (s *Scheduler) Update(id string, schedule Schedule) (Schedule, error) {
+	spec, err := s.validate(&schedule)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.schedules {
+		if existing.ID != id {
+			continue
+		}
+		schedule.ID = id
+		schedule.LastRun = existing.LastRun
+		schedule.NextRun = nil
+		s.schedules[i] = &schedule
+		s.specs[id] = spec
+		s.saveLocked()
+
+		log.Printf("🗓️ Jadval o'zgartirildi: %s (%s)", schedule.Name, schedule.Cron)
+		return s.viewLocked(&schedule, time.Now()), nil
+	}
+	return Schedule{}, ErrScheduleNotFound
+}
+
+// Delete - jadvalni o'chiradi
+func (s *Scheduler) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, schedule := range s.schedules {
+		if schedule.ID == id {
+			s.schedules = append(s.schedules[:i], s.schedules[i+1:]...)
+			delete(s.specs, id)
+			s.saveLocked()
+			log.Printf("🗑️ Jadval o'chirildi: %s", schedule.Name)
+			return true
+		}
+	}
+	return false
+}
+
+// Holidays - bayram kunlari ro'yxati
+func (s *Scheduler) Holidays() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.holidays...)
+}
+
+// SetHolidays - bayram kunlarini almashtiradi: "2026-03-21" (bir martalik) yoki "03-21" (har yili)
+func (s *Scheduler) SetHolidays(holidays []string) ([]string, error) {
+	normalized, err := normalizeHolidays(holidays)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.holidays = normalized
+	s.saveLocked()
+	log.Printf("🗓️ Bayram kunlari yangilandi: %d ta", len(normalized))
+	return append([]string{}, normalized...), nil
+}
+
+// validate - cron, xabar va bayram qoidasini tekshiradi
+func (s *Scheduler) validate(schedule *Schedule) (cronSpec, error) {
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	schedule.Cron = strings.TrimSpace(schedule.Cron)
+	if schedule.Name == "" {
+		return cronSpec{}, fmt.Errorf("jadval nomi bo'sh")
+	}
+	spec, err := parseCron(schedule.Cron)
+	if err != nil {
+		return cronSpec{}, err
+	}
+	switch schedule.Holidays {
+	case "":
+		schedule.Holidays = HolidaySkip
+	case HolidaySkip, HolidayOnly, HolidayAny:
+	default:
+		return cronSpec{}, fmt.Errorf("holidays skip, only yoki any bo'lishi kerak: %q", schedule.Holidays)
+	}
+	if _, err := s.zones.audioService.ResolveBroadcast(Broadcast{MessageID: schedule.MessageID, Items: schedule.Items}); err != nil {
+		return cronSpec{}, err
+	}
+	if err := s.zones.CheckZones(schedule.Zones); err != nil {
+		return cronSpec{}, err
+	}
+	return spec, nil
+}
+
+// viewLocked - API uchun nusxa, keyingi ijro vaqti bilan
+func (s *Scheduler) viewLocked(schedule *Schedule, now time.Time) Schedule {
+	view := *schedule
+	view.NextRun = nil
+	if !schedule.Enabled {
+		return view
+	}
+	spec, ok := s.specs[schedule.ID]
+	if !ok {
+		return view
+	}
+	// Bayram qoidasi bilan mos kelmaydigan kunlar o'tkazib yuboriladi
+	t := now
+	for i := 0; i < 400; i++ {
+		next, ok := spec.next(t)
+		if !ok {
+			break
+		}
+		if s.holidayAllowsLocked(schedule, next) {
+			view.NextRun = &next
+			break
+		}
+		t = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location()).Add(-time.Minute)
+	}
+	return view
+}
+
+func normalizeHolidays(holidays []string) ([]string, error) {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, holiday := range holidays {
+		holiday = strings.TrimSpace(holiday)
+		if holiday == "" || seen[holiday] {
+			continue
+		}
+		_, errDate := time.Parse("2006-01-02", holiday)
+		_, errYearly := time.Parse("01-02", holiday)
+		if errDate != nil && errYearly != nil {
+			return nil, fmt.Errorf("bayram kuni YYYY-MM-DD yoki MM-DD formatida bo'lishi kerak: %q", holiday)
+		}
+		seen[holiday] = true
+		result = append(result, holiday)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func (s *Scheduler) load() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fayl o'qilmadi: %w", err)
+	}
+
+	var state scheduleState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("fayl buzilgan: %w", err)
+	}
+	for _, schedule := range state.Schedules {
+		spec, err := parseCron(schedule.Cron)
+		if err != nil {
+			// Jadval saqlanadi, lekin tuzatilmaguncha ishlamaydi
+			log.Printf("⚠️ Jadval %q: %v", schedule.Name, err)
+		} else {
+			s.specs[schedule.ID] = spec
+		}
+		s.schedules = append(s.schedules, schedule)
+	}
+	s.holidays = state.Holidays
+	return nil
+}
+
+// saveLocked - vaqtinchalik faylga yozib, keyin almashtiradi (mu ushlangan bo'lishi kerak)
+func (s *Scheduler) saveLocked() {
+	if s.path == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(scheduleState{Schedules: s.schedules, Holidays: s.holidays}, "", "  ")
+	if err != nil {
+		log.Printf("❌ Xabarlar jadvalini serializatsiya qilib bo'lmadi: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		log.Printf("❌ Data papkasini yaratib bo'lmadi: %v", err)
+		return
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		log.Printf("❌ Xabarlar jadvalini yozib bo'lmadi: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		log.Printf("❌ Xabarlar jadvalini almashtirib bo'lmadi: %v", err)
+	}
+}
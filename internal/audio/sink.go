@@ -0,0 +1,225 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+)
+
+// ==============================
+// CHIQISH QURILMALARI (SINK)
+// Tayyor e'lon oqimi qayerga yuborilishini belgilaydi:
+// speaker, aniq chiqish qurilmasi (device), WAV fayl, null (faqat davomiylik)
+// yoki xotira (testlar uchun)
+// ==============================
+
+// Sink turlari (config: audio.sink)
+const (
+	SinkSpeaker = "speaker"
+	SinkDevice  = "device" // Nomi yoki indeksi berilgan qurilma (zona uchun alohida kuchaytirgich)
+	SinkWAV     = "wav"
+	SinkNull    = "null"
+	SinkMemory  = "memory"
+)
+
+// ErrInterrupted - ijro Interrupt orqali to'xtatildi (favqulodda xabar uchun)
+var ErrInterrupted = errors.New("ijro to'xtatildi")
+
+// ErrSilentFallback - asosiy qurilma ishlamadi va e'lon null zaxiraga ketdi:
+// oqim "ijro etildi", lekin zalda hech kim eshitmadi
+var ErrSilentFallback = errors.New("asosiy qurilma ishlamadi, e'lon null zaxiraga ketdi (eshitilmadi)")
+
+// Sink - e'lon oqimini qabul qiluvchi chiqish
+// Play ijro tugaguncha bloklaydi; Interrupt joriy Play ni ErrInterrupted bilan tugatadi
+type Sink interface {
+	Name() string
+	Play(stream beep.StreamSeeker, format beep.Format) error
+	Interrupt()
+	Close() error
+}
+
+// routedSink - bir nechta chiqish orasidan tanlaydigan sink (WatchdogSink)
+type routedSink interface {
+	playRouted(stream beep.StreamSeeker, format beep.Format) (sink string, fallback bool, err error)
+}
+
+// playOn - oqimni ijro etadi; e'lonni haqiqatda qabul qilgan sink nomi va
+// zaxira ishlatilganini ham qaytaradi (tarixda "qaysi karnayda aytildi" uchun)
+func playOn(sink Sink, stream beep.StreamSeeker, format beep.Format) (string, bool, error) {
+	if routed, ok := sink.(routedSink); ok {
+		return routed.playRouted(stream, format)
+	}
+	return sink.Name(), false, sink.Play(stream, format)
+}
+
+// playTimeout - oqim davomiyligiga qarab kutish chegarasi: davomiylik + 25% + 2 soniya
+// Qisqa e'lon qotib qolgan qurilmada navbatni uzoq to'xtatmaydi, uzun xabar esa uzilmaydi
+func playTimeout(d time.Duration) time.Duration {
+	return d + d/4 + 2*time.Second
+}
+
+// NewSink - sozlamadagi nom bo'yicha sink yaratadi
+// dir - WAV sink uchun fayllar papkasi, device - device sink uchun qurilma nomi yoki indeksi
+func NewSink(kind, dir, device string) (Sink, error) {
+	switch kind {
+	case "", SinkSpeaker:
+		return NewSpeakerSink(), nil
+	case SinkDevice:
+		return NewDeviceSink(device)
+	case SinkWAV:
+		return NewWAVSink(dir)
+	case SinkNull:
+		return NewNullSink(), nil
+	case SinkMemory:
+		return NewMemorySink(), nil
+	default:
+		return nil, fmt.Errorf("noma'lum audio sink: %q (speaker, device, wav, null, memory)", kind)
+	}
+}
+
+// ==============================
+// WAV FAYL
+// ==============================
+
+// WAVSink - har bir e'lonni alohida WAV faylga yozadi
+type WAVSink struct {
+	dir   string
+	mu    sync.Mutex
+	count uint64
+}
+
+func NewWAVSink(dir string) (*WAVSink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("wav sink papkasi yaratilmadi: %w", err)
+	}
+	return &WAVSink{dir: dir}, nil
+}
+
+func (s *WAVSink) Name() string { return SinkWAV }
+
+func (s *WAVSink) Play(stream beep.StreamSeeker, format beep.Format) error {
+	s.mu.Lock()
+	s.count++
+	name := fmt.Sprintf("announcement-%s-%04d.wav", time.Now().Format("20060102-150405"), s.count)
+	s.mu.Unlock()
+
+	path := filepath.Join(s.dir, name)
+	if err := writeWAV(path, stream, format); err != nil {
+		return err
+	}
+	log.Printf("💾 E'lon yozildi: %s (%v)", path, format.SampleRate.D(stream.Len()))
+	return nil
+}
+
+// Interrupt - fayl yozish bir zumda tugaydi, to'xtatiladigan narsa yo'q
+func (s *WAVSink) Interrupt() {}
+
+func (s *WAVSink) Close() error { return nil }
+
+// writeWAV - oqimni WAV faylga yozadi
+func writeWAV(path string, stream beep.Streamer, format beep.Format) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("wav fayl yaratilmadi: %w", err)
+	}
+	if err := wav.Encode(f, stream, format); err != nil {
+		f.Close()
+		return fmt.Errorf("wav yozilmadi: %w", err)
+	}
+	return f.Close()
+}
+
+// ==============================
+// NULL
+// ==============================
+
+// NullSink - hech narsa ijro etmaydi, faqat davomiylikni hisoblaydi
+// Ovoz kartasi yo'q serverlar va CI uchun
+type NullSink struct {
+	mu    sync.Mutex
+	count int
+	total time.Duration
+}
+
+func NewNullSink() *NullSink { return &NullSink{} }
+
+func (s *NullSink) Name() string { return SinkNull }
+
+func (s *NullSink) Play(stream beep.StreamSeeker, format beep.Format) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count++
+	s.total += format.SampleRate.D(stream.Len())
+	return nil
+}
+
+func (s *NullSink) Interrupt() {}
+
+func (s *NullSink) Close() error { return nil }
+
+// Stats - "ijro etilgan" e'lonlar soni va umumiy davomiyligi
+func (s *NullSink) Stats() (int, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count, s.total
+}
+
+// ==============================
+// XOTIRA
+// ==============================
+
+// Recording - MemorySink saqlagan bitta e'lon
+type Recording struct {
+	Samples  [][2]float64
+	Format   beep.Format
+	Duration time.Duration
+}
+
+// MemorySink - e'lonlarni xotirada saqlaydi (testlarda natijani tekshirish uchun)
+type MemorySink struct {
+	mu         sync.Mutex
+	recordings []Recording
+}
+
+func NewMemorySink() *MemorySink { return &MemorySink{} }
+
+func (s *MemorySink) Name() string { return SinkMemory }
+
+func (s *MemorySink) Play(stream beep.StreamSeeker, format beep.Format) error {
+	samples := make([][2]float64, 0, stream.Len())
+	buf := make([][2]float64, 512)
+	for {
+		n, ok := stream.Stream(buf)
+		samples = append(samples, buf[:n]...)
+		if !ok {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recordings = append(s.recordings, Recording{
+		Samples:  samples,
+		Format:   format,
+		Duration: format.SampleRate.D(len(samples)),
+	})
+	return nil
+}
+
+func (s *MemorySink) Interrupt() {}
+
+func (s *MemorySink) Close() error { return nil }
+
+// Recordings - saqlangan e'lonlar nusxasi
+func (s *MemorySink) Recordings() []Recording {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Recording(nil), s.recordings...)
+}
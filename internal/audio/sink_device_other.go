@@ -0,0 +1,16 @@
+//go:build !windows
+
+package audio
+
+import "fmt"
+
+// NewDeviceSink - aniq qurilmani ochish hozircha faqat Windows da (winmm)
+// Boshqa tizimlarda zonalar uchun speaker (bittasi), wav yoki null ishlatiladi
+func NewDeviceSink(device string) (Sink, error) {
+	return nil, fmt.Errorf("device sink faqat Windows da ishlaydi (%q)", device)
+}
+
+// OutputDevices - Windows dan boshqa tizimlarda qurilmalar ro'yxati yo'q
+func OutputDevices() []string {
+	return nil
+}
@@ -0,0 +1,256 @@
+//go:build windows
+
+package audio
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/faiface/beep"
+	"golang.org/x/sys/windows"
+)
+
+// ==============================
+// ALOHIDA CHIQISH QURILMASI (Windows, winmm)
+// speaker paketi faqat standart qurilmani ochadi va butun dasturda bitta.
+// Har bir qavat kuchaytirgichi alohida ovoz kartasi yoki USB chiqishga
+// ulangan bo'lsa, DeviceSink zona uchun aynan shu qurilmani waveOut orqali
+// ochadi. Qurilma har bir e'lon uchun ochilib yopiladi: uzilib qayta
+// ulangan qurilma keyingi e'londa o'zi topiladi.
+// ==============================
+
+var (
+	winmm                      = windows.NewLazySystemDLL("winmm.dll")
+	procWaveOutGetNumDevs      = winmm.NewProc("waveOutGetNumDevs")
+	procWaveOutGetDevCapsW     = winmm.NewProc("waveOutGetDevCapsW")
+	procWaveOutOpen            = winmm.NewProc("waveOutOpen")
+	procWaveOutPrepareHeader   = winmm.NewProc("waveOutPrepareHeader")
+	procWaveOutUnprepareHeader = winmm.NewProc("waveOutUnprepareHeader")
+	procWaveOutWrite           = winmm.NewProc("waveOutWrite")
+	procWaveOutReset           = winmm.NewProc("waveOutReset")
+	procWaveOutClose           = winmm.NewProc("waveOutClose")
+)
+
+const (
+	waveFormatPCM = 1
+	whdrDone      = 0x1
+	callbackNull  = 0
+
+	// devicePoll - ijro tugaganini tekshirish oralig'i
+	devicePoll = 20 * time.Millisecond
+)
+
+// waveFormatEx - WAVEFORMATEX
+type waveFormatEx struct {
+	formatTag      uint16
+	channels       uint16
+	samplesPerSec  uint32
+	avgBytesPerSec uint32
+	blockAlign     uint16
+	bitsPerSample  uint16
+	size           uint16
+}
+
+// waveHeader - WAVEHDR
+type waveHeader struct {
+	data          uintptr
+	bufferLength  uint32
+	bytesRecorded uint32
+	user          uintptr
+	flags         uint32
+	loops         uint32
+	next          uintptr
+	reserved      uintptr
+}
+
+// waveOutCaps - WAVEOUTCAPSW
+type waveOutCaps struct {
+	mid           uint16
+	pid           uint16
+	driverVersion uint32
+	name          [32]uint16
+	formats       uint32
+	channels      uint16
+	reserved      uint16
+	support       uint32
+}
+
+// mmCall - winmm funksiyasini chaqiradi; MMRESULT 0 bo'lmasa xato
+func mmCall(proc *windows.LazyProc, args ...uintptr) error {
+	if r, _, _ := proc.Call(args...); r != 0 {
+		return fmt.Errorf("%s: MMRESULT %d", proc.Name, r)
+	}
+	return nil
+}
+
+// OutputDevices - chiqish qurilmalari nomlari, indeks tartibida
+// Windows nomni 31 belgigacha qisqartiradi, sozlamada nomning bir qismi yetarli
+func OutputDevices() []string {
+	count, _, _ := procWaveOutGetNumDevs.Call()
+	names := make([]string, 0, count)
+	for id := uintptr(0); id < count; id++ {
+		var caps waveOutCaps
+		if err := mmCall(procWaveOutGetDevCapsW, id, uintptr(unsafe.Pointer(&caps)), unsafe.Sizeof(caps)); err != nil {
+			names = append(names, "")
+			continue
+		}
+		names = append(names, windows.UTF16ToString(caps.name[:]))
+	}
+	return names
+}
+
+// findDevice - qurilmani indeks yoki nom (katta-kichik harfsiz, bir qismi) bo'yicha topadi
+// Indekslar qurilma ulanib-uzilganda o'zgaradi, shuning uchun har safar qaytadan qidiriladi
+func findDevice(device string) (uint32, string, error) {
+	names := OutputDevices()
+	if index, err := strconv.Atoi(device); err == nil {
+		if index < 0 || index >= len(names) {
+			return 0, "", fmt.Errorf("chiqish qurilmasi #%d yo'q (mavjud: %q)", index, names)
+		}
+		return uint32(index), names[index], nil
+	}
+
+	want := strings.ToLower(device)
+	for id, name := range names {
+		if name != "" && strings.Contains(strings.ToLower(name), want) {
+			return uint32(id), name, nil
+		}
+	}
+	return 0, "", fmt.Errorf("chiqish qurilmasi topilmadi: %q (mavjud: %q)", device, names)
+}
+
+// DeviceSink - nomi yoki indeksi berilgan chiqish qurilmasi (waveOut)
+// speaker dan farqli ravishda bir nechta zona har biri o'z qurilmasini ishlatadi
+type DeviceSink struct {
+	device string // Sozlamadagi nom (bir qismi) yoki indeks
+
+	mu        sync.Mutex
+	interrupt chan struct{} // Joriy Play uchun, Interrupt yopadi
+}
+
+// NewDeviceSink - qurilma hozir topilmasa ham sink yaratiladi (USB keyin ulanishi mumkin),
+// Play xato qaytaradi va watchdog zaxiraga o'tadi
+func NewDeviceSink(device string) (Sink, error) {
+	device = strings.TrimSpace(device)
+	if device == "" {
+		return nil, fmt.Errorf("device sink uchun qurilma ko'rsatilmagan (zones[].device)")
+	}
+	if id, name, err := findDevice(device); err != nil {
+		log.Printf("⚠️ %v", err)
+	} else {
+		log.Printf("🔊 Chiqish qurilmasi: #%d %s", id, name)
+	}
+	return &DeviceSink{device: device}, nil
+}
+
+func (s *DeviceSink) Name() string { return SinkDevice + ":" + s.device }
+
+// Play - oqimni qurilmaga yozadi va tugashini kutadi
+func (s *DeviceSink) Play(stream beep.StreamSeeker, format beep.Format) error {
+	id, name, err := findDevice(s.device)
+	if err != nil {
+		return err
+	}
+	timeout := playTimeout(format.SampleRate.D(stream.Len()))
+	pcm := encodePCM16(readSamples(stream, stream.Len()-stream.Position()))
+	if len(pcm) == 0 {
+		return nil
+	}
+
+	wf := waveFormatEx{
+		formatTag:      waveFormatPCM,
+		channels:       2,
+		samplesPerSec:  uint32(format.SampleRate),
+		avgBytesPerSec: uint32(format.SampleRate) * 4,
+		blockAlign:     4,
+		bitsPerSample:  16,
+	}
+	var out uintptr
+	if err := mmCall(procWaveOutOpen, uintptr(unsafe.Pointer(&out)), uintptr(id), uintptr(unsafe.Pointer(&wf)), 0, 0, callbackNull); err != nil {
+		return fmt.Errorf("%s ochilmadi: %w", name, err)
+	}
+	defer mmCall(procWaveOutClose, out)
+
+	// Bufer drayverga beriladi: ijro tugaguncha (Unprepare gacha) tirik qolishi kerak
+	header := &waveHeader{data: uintptr(unsafe.Pointer(&pcm[0])), bufferLength: uint32(len(pcm))}
+	headerSize := unsafe.Sizeof(*header)
+	if err := mmCall(procWaveOutPrepareHeader, out, uintptr(unsafe.Pointer(header)), headerSize); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer func() {
+		mmCall(procWaveOutUnprepareHeader, out, uintptr(unsafe.Pointer(header)), headerSize)
+		runtime.KeepAlive(pcm)
+		runtime.KeepAlive(header)
+	}()
+
+	interrupt := make(chan struct{})
+	s.mu.Lock()
+	s.interrupt = interrupt
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		if s.interrupt == interrupt {
+			s.interrupt = nil
+		}
+		s.mu.Unlock()
+	}()
+
+	if err := mmCall(procWaveOutWrite, out, uintptr(unsafe.Pointer(header)), headerSize); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	ticker := time.NewTicker(devicePoll)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+	for {
+		// flags ni drayver o'z oqimida o'zgartiradi
+		if atomic.LoadUint32(&header.flags)&whdrDone != 0 {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-interrupt:
+			mmCall(procWaveOutReset, out)
+			return ErrInterrupted
+		case <-deadline:
+			mmCall(procWaveOutReset, out)
+			return fmt.Errorf("%s: audio timeout", name)
+		}
+	}
+}
+
+// Interrupt - joriy ijroni to'xtatadi (Play waveOutReset qiladi)
+func (s *DeviceSink) Interrupt() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.interrupt == nil {
+		return
+	}
+	close(s.interrupt)
+	s.interrupt = nil
+	log.Printf("⏹️ %s to'xtatildi", s.Name())
+}
+
+// Close - qurilma faqat Play davomida ochiq, yopiladigan narsa yo'q
+func (s *DeviceSink) Close() error { return nil }
+
+// encodePCM16 - stereo namunalarni 16-bit little-endian PCM ga aylantiradi
+func encodePCM16(data [][2]float64) []byte {
+	pcm := make([]byte, 0, len(data)*4)
+	for _, sample := range data {
+		for _, value := range sample {
+			value = max(-1, min(1, value))
+			v := int16(value * (1<<15 - 1))
+			pcm = append(pcm, byte(v), byte(v>>8))
+		}
+	}
+	return pcm
+}
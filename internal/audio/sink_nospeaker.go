@@ -0,0 +1,31 @@
+//go:build nospeaker
+
+package audio
+
+import (
+	"fmt"
+
+	"github.com/faiface/beep"
+)
+
+// SpeakerSink - nospeaker build'da ovoz kartasi yo'q (ALSA/oto talab qilinmaydi)
+// Bunday build'da audio.sink "wav", "null" yoki "memory" bo'lishi kerak
+type SpeakerSink struct{}
+
+func NewSpeakerSink() Sink {
+	return &SpeakerSink{}
+}
+
+func (s *SpeakerSink) Name() string { return SinkSpeaker }
+
+func (s *SpeakerSink) Play(stream beep.StreamSeeker, format beep.Format) error {
+	return fmt.Errorf("speaker bu build'da o'chirilgan (nospeaker)")
+}
+
+func (s *SpeakerSink) Reinit() error {
+	return fmt.Errorf("speaker bu build'da o'chirilgan (nospeaker)")
+}
+
+func (s *SpeakerSink) Interrupt() {}
+
+func (s *SpeakerSink) Close() error { return nil }
@@ -0,0 +1,175 @@
+//go:build !nospeaker
+
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/speaker"
+)
+
+// SpeakerSink - kompyuter ovoz kartasi (faiface/beep/speaker)
+// Speaker bir marta, birinchi e'lon formatida ochiladi; barcha kliplar
+// oldindan shu chastotaga keltirilgani uchun tezlik va ohang buzilmaydi
+type SpeakerSink struct {
+	mu          sync.Mutex
+	initialized bool
+	opening     bool // speaker.Init hali qaytmagan: speaker paketi qulflangan, unga tegilmaydi
+	sampleRate  beep.SampleRate
+	interrupt   chan struct{} // Joriy Play uchun, Interrupt yopadi
+}
+
+// errSpeakerOpening - oldingi speaker.Init hali qaytmagan (drayver qotib qolgan bo'lishi mumkin)
+var errSpeakerOpening = errors.New("speaker hali ochilmoqda")
+
+func NewSpeakerSink() Sink {
+	return &SpeakerSink{}
+}
+
+func (s *SpeakerSink) Name() string { return SinkSpeaker }
+
+func (s *SpeakerSink) init(sampleRate beep.SampleRate) error {
+	s.mu.Lock()
+	initialized, opening := s.initialized, s.opening
+	s.mu.Unlock()
+
+	if opening {
+		return errSpeakerOpening
+	}
+	if initialized {
+		return nil
+	}
+
+	if err := s.open(sampleRate); err != nil {
+		return fmt.Errorf("speaker init: %w", err)
+	}
+	log.Printf("🔊 Speaker initialized (SampleRate: %d)", sampleRate)
+	return nil
+}
+
+// Reinit - qurilmani yopib, qayta ochadi (quloqchin uzilganda, RDP sessiyasi almashganda)
+// Hali ochilmagan bo'lsa, birinchi Play da ochiladi.
+// speaker.Init qotib qolsa, Reinit qaytmaydi, lekin sink bloklanmaydi: chaqiruvchi
+// uni tashlab ketishi mumkin, keyingi Reinit va Play darhol xato qaytaradi
+func (s *SpeakerSink) Reinit() error {
+	s.mu.Lock()
+	initialized, sampleRate := s.initialized, s.sampleRate
+	s.mu.Unlock()
+
+	if !initialized {
+		return nil
+	}
+	if err := s.open(sampleRate); err != nil {
+		return fmt.Errorf("speaker qayta ochilmadi: %w", err)
+	}
+	log.Printf("🔊 Speaker qayta ochildi (SampleRate: %d)", sampleRate)
+	return nil
+}
+
+// open - speaker.Init ni mu ushlamasdan chaqiradi
+// Init ishlayotganda speaker paketi o'z qulfini ushlaydi: Play, Interrupt va Close
+// opening ni ko'rib speaker ga murojaat qilmaydi, shuning uchun ular ham qotmaydi
+func (s *SpeakerSink) open(sampleRate beep.SampleRate) error {
+	s.mu.Lock()
+	if s.opening {
+		s.mu.Unlock()
+		return errSpeakerOpening
+	}
+	s.opening = true
+	s.mu.Unlock()
+
+	err := speaker.Init(sampleRate, bufferSize(sampleRate))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opening = false
+	s.initialized = err == nil
+	if err == nil {
+		s.sampleRate = sampleRate
+	}
+	return err
+}
+
+func bufferSize(sampleRate beep.SampleRate) int {
+	if runtime.GOOS == "windows" {
+		return sampleRate.N(time.Second / 5)
+	}
+	return sampleRate.N(time.Second / 10)
+}
+
+// Play - oqimni ijro etadi va tugashini kutadi
+// Timeout oqim davomiyligidan hisoblanadi (playTimeout), uzun e'lonlar ham uzilib qolmaydi
+func (s *SpeakerSink) Play(stream beep.StreamSeeker, format beep.Format) error {
+	if err := s.init(format.SampleRate); err != nil {
+		return err
+	}
+
+	done := make(chan bool, 1) // ⚠️ Buffered channel!
+	interrupt := make(chan struct{})
+	s.mu.Lock()
+	s.interrupt = interrupt
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		if s.interrupt == interrupt {
+			s.interrupt = nil
+		}
+		s.mu.Unlock()
+	}()
+
+	speaker.Play(beep.Seq(stream, beep.Callback(func() {
+		select {
+		case done <- true:
+		default:
+		}
+	})))
+
+	// ⚠️ Timeout qo'shamiz - deadlock oldini olish
+	timeout := playTimeout(format.SampleRate.D(stream.Len()))
+	select {
+	case <-done:
+		return nil
+	case <-interrupt:
+		return ErrInterrupted
+	case <-time.After(timeout):
+		s.mu.Lock()
+		if !s.opening {
+			speaker.Clear()
+		}
+		s.mu.Unlock()
+		return fmt.Errorf("audio timeout")
+	}
+}
+
+// Interrupt - ovozni darhol o'chiradi (speaker.Clear) va joriy Play ni tugatadi
+func (s *SpeakerSink) Interrupt() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.interrupt == nil {
+		return
+	}
+	if !s.opening {
+		speaker.Clear()
+	}
+	close(s.interrupt)
+	s.interrupt = nil
+	log.Println("⏹️ Speaker to'xtatildi")
+}
+
+func (s *SpeakerSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.initialized && !s.opening {
+		speaker.Clear()
+		log.Println("🔊 Speaker tozalandi")
+	}
+	return nil
+}
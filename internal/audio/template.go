@@ -0,0 +1,208 @@
+package audio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ==============================
+// E'LON SHABLONLARI
+// E'lon ketma-ketligi tokenlar ro'yxati sifatida sozlanadi va bo'lim
+// yoki shifokor bo'yicha tanlanadi. Har bir element:
+//   {prefix}      - navbat harfi ("K-015" dagi K), harfsiz raqamda tushib qoladi
+//   {number}      - navbat raqami
+//   {room}        - xona raqami
+//   {doctor}      - shifokor ismi (doctors/<id>)
+//   {department}  - bo'lim yoki mutaxassislik nomi (departments/<nom>)
+//   phrases/...   - istalgan tayyor token
+// "ru:" kabi old qo'shimcha elementni boshqa til paketidan o'qiydi:
+//   ["{number}", "phrases/raqam_egasi", ..., "ru:phrases/smotrite_tablo"]
+// {number} va {room} o'zbekcha qoidalar bilan yig'iladi (NumberClips, RoomClips),
+// shuning uchun boshqa til paketlarida ishlatib bo'lmaydi
+// ==============================
+
+// DefaultTemplateName - sozlanmagan bo'lim va shifokorlar uchun shablon
+const DefaultTemplateName = "default"
+
+// Shablon o'zgaruvchilari
+const (
+	SlotPrefix         = "{prefix}"
+	SlotNumber         = "{number}"
+	SlotRoom           = "{room}"
+	SlotDoctor         = "{doctor}"
+	SlotDepartment     = "{department}"
+	SlotSpecialization = "{specialization}" // {department} bilan bir xil
+)
+
+// DefaultTemplate - harf, raqam, "raqam egasi", xona, "xonaga keling"
+var DefaultTemplate = []string{SlotPrefix, SlotNumber, "phrases/raqam_egasi", SlotRoom, "phrases/honaga_kelishin"}
+
+// Templates - nomlangan shablonlar va ularning bo'lim/shifokorlarga bog'lanishi
+type Templates struct {
+	Named       map[string][]string // Nom -> elementlar
+	Default     string              // Standart shablon nomi, bo'sh bo'lsa "default"
+	Departments map[string]string   // Bo'lim nomi -> shablon nomi
+	Doctors     map[string]string   // Shifokor ID -> shablon nomi
+}
+
+// Validate - shablonlar sintaksisi va havolalarini tekshiradi
+// Fayllar mavjudligi bu yerda emas, AudioService.ValidateTemplates da tekshiriladi
+func (t *Templates) Validate() error {
+	if t.Named == nil {
+		t.Named = map[string][]string{}
+	}
+	if t.Default == "" {
+		t.Default = DefaultTemplateName
+	}
+	if _, ok := t.Named[DefaultTemplateName]; !ok {
+		t.Named[DefaultTemplateName] = DefaultTemplate
+	}
+
+	for name, items := range t.Named {
+		if len(items) == 0 {
+			return fmt.Errorf("shablon %q bo'sh", name)
+		}
+		for _, item := range items {
+			if err := validateItem(item); err != nil {
+				return fmt.Errorf("shablon %q: %w", name, err)
+			}
+		}
+	}
+
+	if _, ok := t.Named[t.Default]; !ok {
+		return fmt.Errorf("standart shablon topilmadi: %q", t.Default)
+	}
+	for department, name := range t.Departments {
+		if _, ok := t.Named[name]; !ok {
+			return fmt.Errorf("bo'lim %q uchun shablon topilmadi: %q", department, name)
+		}
+	}
+	for doctor, name := range t.Doctors {
+		if _, ok := t.Named[name]; !ok {
+			return fmt.Errorf("shifokor %q uchun shablon topilmadi: %q", doctor, name)
+		}
+	}
+	return nil
+}
+
+// Select - e'lon uchun shablon: avval shifokor, keyin bo'lim, keyin standart
+func (t *Templates) Select(req Announcement) (string, []string) {
+	if name, ok := t.Doctors[req.DoctorID]; ok && req.DoctorID != "" {
+		return name, t.Named[name]
+	}
+	for department, name := range t.Departments {
+		if strings.EqualFold(department, req.DepartmentName) {
+			return name, t.Named[name]
+		}
+	}
+	return t.Default, t.Named[t.Default]
+}
+
+// splitItem - "ru:{number}" -> ("ru", "{number}"); til ko'rsatilmasa ""
+func splitItem(item string) (string, string) {
+	if language, body, ok := strings.Cut(item, ":"); ok {
+		return language, body
+	}
+	return "", item
+}
+
+func validateItem(item string) error {
+	language, body := splitItem(item)
+	if body == "" {
+		return fmt.Errorf("bo'sh element: %q", item)
+	}
+	if strings.HasPrefix(body, "{") {
+		switch body {
+		case SlotPrefix, SlotNumber, SlotRoom, SlotDoctor, SlotDepartment, SlotSpecialization:
+		default:
+			return fmt.Errorf("noma'lum o'zgaruvchi: %q", body)
+		}
+	}
+	if (body == SlotNumber || body == SlotRoom) && language != "" && !composesNumbers(language) {
+		return fmt.Errorf("%q: %s paketida raqamlarni yig'ib bo'lmaydi (faqat o'zbek paketi)", item, language)
+	}
+	if strings.Contains(item, ":") && language == "" {
+		return fmt.Errorf("til ko'rsatilmagan: %q", item)
+	}
+	return nil
+}
+
+// composesNumbers - til paketida raqam va xonani NumberClips qoidalari bilan yig'ish mumkinmi
+// Qoidalar o'zbekcha ("20a" shakllari, o'zbekcha tartib): ruscha "двадцать" yoki
+// "двести" ni bunday yig'ib bo'lmaydi, boshqa tillar uchun yig'uvchi yo'q
+func composesNumbers(language string) bool {
+	language = strings.ToLower(language)
+	return language == "uz" || strings.HasPrefix(language, "uz-")
+}
+
+// DoctorToken - shifokor ismi klipi: "doctors/<id>"
+func DoctorToken(id string) string {
+	return nameToken("doctors", id)
+}
+
+// DepartmentToken - bo'lim nomi klipi: "Kardiologiya" -> "departments/kardiologiya"
+func DepartmentToken(name string) string {
+	return nameToken("departments", name)
+}
+
+// nameToken - "Kardiologiya" -> "departments/kardiologiya"
+func nameToken(dir, name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return dir + "/" + strings.Join(strings.Fields(name), "_")
+}
+
+// literalTokens - shablonlardagi tayyor tokenlar, til bo'yicha
+// defaultLanguage - old qo'shimchasiz elementlar tili
+func (t *Templates) literalTokens(defaultLanguage string) map[string][]string {
+	result := make(map[string][]string)
+	seen := make(map[string]bool)
+
+	names := make([]string, 0, len(t.Named))
+	for name := range t.Named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, item := range t.Named[name] {
+			language, body := splitItem(item)
+			if strings.HasPrefix(body, "{") {
+				continue
+			}
+			if language == "" {
+				language = defaultLanguage
+			}
+			key := language + ":" + body
+			if !seen[key] {
+				seen[key] = true
+				result[language] = append(result[language], body)
+			}
+		}
+	}
+	return result
+}
+
+// slotLanguages - {number} va {room} qaysi tillarda aytiladi
+func (t *Templates) slotLanguages(defaultLanguage string) map[string]map[string]bool {
+	result := make(map[string]map[string]bool)
+	for _, items := range t.Named {
+		for _, item := range items {
+			language, body := splitItem(item)
+			if !strings.HasPrefix(body, "{") {
+				continue
+			}
+			if language == "" {
+				language = defaultLanguage
+			}
+			if result[language] == nil {
+				result[language] = make(map[string]bool)
+			}
+			if body == SlotSpecialization {
+				body = SlotDepartment
+			}
+			result[language][body] = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,173 @@
+package audio
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==============================
+// TTS ZAXIRASI
+// Ovoz paketida yo'q tokenlar mahalliy TTS dasturi (espeak-ng, piper) orqali
+// WAV ga aylantiriladi va diskda saqlanadi. Keyingi safar dastur chaqirilmaydi.
+// Argumentlarda: {text} - matn, {out} - chiqish fayli, {lang} - til paketi.
+// {text} bo'lmasa, matn stdin orqali beriladi (piper shunday ishlaydi):
+//   espeak-ng: ["-v", "uz", "-w", "{out}", "{text}"]
+//   piper:     ["--model", "uz.onnx", "--output_file", "{out}"]
+// ==============================
+
+// DefaultTTSTimeout - bitta ibora uchun TTS dasturi kutiladigan vaqt
+const DefaultTTSTimeout = 30 * time.Second
+
+// TTS - mahalliy TTS dasturi va uning disk keshi
+type TTS struct {
+	command string
+	args    []string
+	dir     string
+	timeout time.Duration
+	texts   map[string]string // Token -> aytiladigan matn (shifokor ismlari va h.k.)
+
+	mu sync.Mutex // Bir vaqtda bitta sintez (bir xil faylni ikki marta yozmaslik uchun)
+}
+
+// NewTTS - TTS zaxirasini yaratadi, dir - sintez qilingan WAV fayllar papkasi
+func NewTTS(command string, args []string, dir string, timeout time.Duration, texts map[string]string) (*TTS, error) {
+	if command == "" {
+		return nil, fmt.Errorf("TTS dasturi ko'rsatilmagan")
+	}
+	if _, err := exec.LookPath(command); err != nil {
+		return nil, fmt.Errorf("TTS dasturi topilmadi: %s", command)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("TTS papkasi yaratilmadi: %w", err)
+	}
+	if timeout <= 0 {
+		timeout = DefaultTTSTimeout
+	}
+	if texts == nil {
+		texts = map[string]string{}
+	}
+	return &TTS{command: command, args: args, dir: dir, timeout: timeout, texts: texts}, nil
+}
+
+// Text - token uchun aytiladigan matn
+// Tartib: manifestdagi "texts", sozlamalardagi matnlar, tokenning o'zidan yasalgan matn
+func (t *TTS) Text(pack *SoundPack, token string) string {
+	if pack != nil {
+		if text, ok := pack.Texts[token]; ok {
+			return text
+		}
+	}
+	if text, ok := t.texts[token]; ok {
+		return text
+	}
+	return tokenText(token)
+}
+
+// Synthesize - token uchun WAV faylni qaytaradi (to'liq yo'l)
+// Fayl keshda bo'lsa, dastur chaqirilmaydi
+func (t *TTS) Synthesize(language, text string) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", fmt.Errorf("TTS uchun matn bo'sh")
+	}
+
+	out, ok := t.Cached(language, text)
+	if ok {
+		return out, nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if info, err := os.Stat(out); err == nil && info.Size() > 0 {
+		return out, nil
+	}
+
+	tmp := strings.TrimSuffix(out, ".wav") + ".tmp.wav"
+	if err := t.run(language, text, tmp); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	if info, err := os.Stat(tmp); err != nil || info.Size() == 0 {
+		os.Remove(tmp)
+		return "", fmt.Errorf("TTS dasturi fayl yaratmadi: %q", text)
+	}
+	if err := os.Rename(tmp, out); err != nil {
+		return "", err
+	}
+
+	log.Printf("🗣️ TTS: %q [%s] -> %s", text, language, filepath.Base(out))
+	return out, nil
+}
+
+// Cached - keshdagi fayl yo'li va u mavjudmi (dastur chaqirilmaydi)
+func (t *TTS) Cached(language, text string) (string, bool) {
+	out := filepath.Join(t.dir, t.cacheKey(language, text)+".wav")
+	info, err := os.Stat(out)
+	return out, err == nil && info.Size() > 0
+}
+
+// run - TTS dasturini bir marta ishga tushiradi
+func (t *TTS) run(language, text, out string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+
+	stdin := true
+	args := make([]string, 0, len(t.args))
+	for _, arg := range t.args {
+		if strings.Contains(arg, "{text}") {
+			stdin = false
+		}
+		arg = strings.ReplaceAll(arg, "{text}", text)
+		arg = strings.ReplaceAll(arg, "{out}", out)
+		arg = strings.ReplaceAll(arg, "{lang}", language)
+		args = append(args, arg)
+	}
+
+	cmd := exec.CommandContext(ctx, t.command, args...)
+	if stdin {
+		cmd.Stdin = strings.NewReader(text)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("TTS xato: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// cacheKey - dastur, argumentlar, til va matn bo'yicha fayl nomi
+// Sozlama o'zgarsa, eski fayllar ishlatilmaydi
+func (t *TTS) cacheKey(language, text string) string {
+	sum := sha1.Sum([]byte(t.command + "\x00" + strings.Join(t.args, "\x00") + "\x00" + language + "\x00" + text))
+	return hex.EncodeToString(sum[:8])
+}
+
+// tokenText - tokendan matn: "phrases/honaga_kelishin" -> "honaga kelishin",
+// "numbers/20a" -> "20", "numbers/316-xona" -> "316 xona"
+func tokenText(token string) string {
+	dir, name, ok := strings.Cut(token, "/")
+	if !ok {
+		name, dir = dir, ""
+	}
+	if trimmed := strings.TrimSuffix(name, "a"); dir == "numbers" && trimmed != name {
+		if _, err := strconv.Atoi(trimmed); err == nil {
+			name = trimmed
+		}
+	}
+	name = strings.NewReplacer("_", " ", "-", " ").Replace(name)
+	if dir == "letters" {
+		name = strings.ToUpper(name)
+	}
+	return name
+}
@@ -0,0 +1,97 @@
+package audio
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// buildTTSStub - cmd/tts-stub ni vaqtinchalik papkaga yig'adi
+func buildTTSStub(t *testing.T) string {
+	t.Helper()
+
+	if testing.Short() {
+		t.Skip("tts-stub yig'iladi (-short da o'tkazib yuboriladi)")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go topilmadi, tts-stub yig'ib bo'lmaydi")
+	}
+	stub := filepath.Join(t.TempDir(), "tts-stub")
+	if runtime.GOOS == "windows" {
+		stub += ".exe"
+	}
+	build := exec.Command(goTool, "build", "-o", stub, filepath.Join("..", "..", "cmd", "tts-stub"))
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("tts-stub yig'ilmadi: %v\n%s", err, output)
+	}
+	return stub
+}
+
+func TestTTSCache(t *testing.T) {
+	stub := buildTTSStub(t)
+	tts, err := NewTTS(stub, []string{"{out}", "{text}"}, t.TempDir(), 10*time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := tts.Cached(DefaultLanguage, "ming"); ok {
+		t.Fatal("sintezdan oldin keshda fayl bo'lmasligi kerak")
+	}
+	file, err := tts.Synthesize(DefaultLanguage, "ming")
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	buffer, err := decodeFile(file, outputFormat(testRate))
+	if err != nil || buffer.Len() == 0 {
+		t.Fatalf("sintez qilingan fayl o'qilmadi: %v", err)
+	}
+	if cached, ok := tts.Cached(DefaultLanguage, "ming"); !ok || cached != file {
+		t.Fatalf("Cached = %q, %v; kutilgan %q", cached, ok, file)
+	}
+
+	// Keshdagi fayl bo'lsa dastur qayta chaqirilmaydi: belgilangan fayl o'zgarmay qaytadi
+	if err := os.WriteFile(file, []byte("kesh"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	again, err := tts.Synthesize(DefaultLanguage, "ming")
+	if err != nil || again != file {
+		t.Fatalf("qayta Synthesize = %q, %v", again, err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "kesh" {
+		t.Fatal("keshdagi fayl bo'la turib TTS dasturi qayta ishga tushdi")
+	}
+
+	// Boshqa til - boshqa fayl
+	other, err := tts.Synthesize("ru", "ming")
+	if err != nil || other == file {
+		t.Fatalf("ru uchun fayl = %q, %v", other, err)
+	}
+}
+
+func TestTTSTimeout(t *testing.T) {
+	stub := buildTTSStub(t)
+	t.Setenv("TTS_STUB_DELAY", "10s")
+	dir := t.TempDir()
+	tts, err := NewTTS(stub, []string{"{out}", "{text}"}, dir, 300*time.Millisecond, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if _, err := tts.Synthesize(DefaultLanguage, "ming"); err == nil {
+		t.Fatal("qotib qolgan TTS dasturi xato qaytarishi kerak")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("timeout ishlamadi: %v kutildi", elapsed)
+	}
+	if _, ok := tts.Cached(DefaultLanguage, "ming"); ok {
+		t.Fatal("muvaffaqiyatsiz sintez keshga tushmasligi kerak")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("vaqtinchalik fayllar qoldi: %v", entries)
+	}
+}
@@ -0,0 +1,312 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// ==============================
+// QURILMA NAZORATCHISI (WATCHDOG)
+// Windows da quloqchin uzilsa yoki RDP sessiyasi almashsa, speaker javob
+// bermay qoladi va har bir e'lon timeout gacha navbatni to'xtatib turadi.
+// Ketma-ket xatolardan keyin e'lonlar zaxira sinkga yo'naltiriladi, asosiy
+// sink esa fonda qayta ochib ko'riladi; ishlasa, yana unga qaytiladi.
+// ==============================
+
+// Qurilma holatlari
+const (
+	DeviceOK       = "ok"       // Asosiy sink ishlayapti
+	DeviceFallback = "fallback" // Asosiy sink ishlamadi, zaxira sink ishlatilmoqda
+)
+
+// DefaultWatchdogFailures - zaxiraga o'tish uchun ketma-ket xatolar soni
+const DefaultWatchdogFailures = 2
+
+// DefaultRecoveryInterval - asosiy sinkni qayta ochish urinishlari oralig'i
+const DefaultRecoveryInterval = 30 * time.Second
+
+// reinitTimeout - qurilmani qayta ochish kutiladigan vaqt
+const reinitTimeout = 10 * time.Second
+
+// probeLength - tiklanganini tekshirish uchun ijro etiladigan sukunat
+const probeLength = 200 * time.Millisecond
+
+// Reinitializer - qayta ochilishi mumkin bo'lgan sink (speaker)
+// Reinit qotib qolsa, watchdog uni kutmasdan tashlab ketadi: sink o'zi
+// keyingi chaqiruvlarda bloklanmasligi (darhol xato qaytarishi) kerak
+type Reinitializer interface {
+	Reinit() error
+}
+
+// DeviceState - chiqish qurilmasi holati (health uchun)
+type DeviceState struct {
+	State            string     `json:"state"` // ok, fallback
+	Primary          string     `json:"primary"`
+	Fallback         string     `json:"fallback,omitempty"`
+	Active           string     `json:"active"` // Hozir e'lonlar qaysi sinkka ketmoqda
+	Failures         int        `json:"consecutive_failures"`
+	LastError        string     `json:"last_error,omitempty"`
+	LastFailure      *time.Time `json:"last_failure,omitempty"`
+	Since            *time.Time `json:"since,omitempty"` // Zaxiraga o'tilgan vaqt
+	RecoveryAttempts int        `json:"recovery_attempts"`
+	Recoveries       int        `json:"recoveries"`
+}
+
+// WatchdogSink - asosiy sinkni kuzatadi va kerak bo'lsa zaxiraga o'tadi
+type WatchdogSink struct {
+	primary     Sink
+	fallback    Sink
+	maxFailures int
+	interval    time.Duration
+
+	mu         sync.Mutex
+	state      DeviceState
+	format     beep.Format // Oxirgi e'lon formati (sinov sukunati uchun)
+	busy       bool        // Asosiy sinkdagi Play hali qaytmagan (qotib qolgan)
+	recovering bool
+	stop       chan struct{}
+	stopOnce   sync.Once
+}
+
+// NewWatchdogSink - primary ni kuzatadi, ishlamasa fallback ga o'tadi
+func NewWatchdogSink(primary, fallback Sink, interval time.Duration) *WatchdogSink {
+	if interval <= 0 {
+		interval = DefaultRecoveryInterval
+	}
+	return &WatchdogSink{
+		primary:     primary,
+		fallback:    fallback,
+		maxFailures: DefaultWatchdogFailures,
+		interval:    interval,
+		state: DeviceState{
+			State:    DeviceOK,
+			Primary:  primary.Name(),
+			Fallback: fallback.Name(),
+			Active:   primary.Name(),
+		},
+		stop: make(chan struct{}),
+	}
+}
+
+func (w *WatchdogSink) Name() string { return w.primary.Name() }
+
+// State - qurilma holati nusxasi
+func (w *WatchdogSink) State() DeviceState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state
+}
+
+// Play - asosiy sink ishlayotgan bo'lsa unga, aks holda zaxiraga
+func (w *WatchdogSink) Play(stream beep.StreamSeeker, format beep.Format) error {
+	_, _, err := w.playRouted(stream, format)
+	return err
+}
+
+// playRouted - Play kabi, e'lon qaysi sinkka ketganini ham qaytaradi
+func (w *WatchdogSink) playRouted(stream beep.StreamSeeker, format beep.Format) (string, bool, error) {
+	w.mu.Lock()
+	w.format = format
+	state, busy := w.state.State, w.busy
+	w.mu.Unlock()
+
+	if state != DeviceOK {
+		return w.fallback.Name(), true, w.fallback.Play(stream, format)
+	}
+	// Oldingi ijro hali qaytmagan - qurilma qotib qolgan, kutish foydasiz
+	if busy {
+		w.failed(fmt.Errorf("%s oldingi ijrodan qaytmadi", w.primary.Name()), true)
+		return w.fallback.Name(), true, w.fallback.Play(stream, format)
+	}
+
+	err := w.playPrimary(stream, format)
+	if err == nil || errors.Is(err, ErrInterrupted) {
+		w.succeeded()
+		return w.primary.Name(), false, err
+	}
+	if !w.failed(err, false) {
+		return w.primary.Name(), false, err
+	}
+
+	// Zaxiraga o'tildi: shu e'lon ham yo'qolmasin (asosiy sink oqimni qo'yib yuborgan bo'lsa)
+	w.mu.Lock()
+	busy = w.busy
+	w.mu.Unlock()
+	if busy {
+		return w.primary.Name(), false, err
+	}
+	if seekErr := stream.Seek(0); seekErr != nil {
+		return w.primary.Name(), false, err
+	}
+	return w.fallback.Name(), true, w.fallback.Play(stream, format)
+}
+
+// playPrimary - asosiy sinkda ijro; sink o'zi qaytmasa ham oqim davomiyligidan keyin xato
+func (w *WatchdogSink) playPrimary(stream beep.StreamSeeker, format beep.Format) error {
+	w.mu.Lock()
+	w.busy = true
+	w.mu.Unlock()
+
+	result := make(chan error, 1)
+	go func() {
+		err := w.primary.Play(stream, format)
+		w.mu.Lock()
+		w.busy = false
+		w.mu.Unlock()
+		result <- err
+	}()
+
+	// Sinkning o'z timeout i birinchi ishlashi uchun bir soniya ko'proq
+	timeout := playTimeout(format.SampleRate.D(stream.Len())) + time.Second
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("%s %v ichida javob bermadi", w.primary.Name(), timeout)
+	}
+}
+
+func (w *WatchdogSink) succeeded() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.state.Failures = 0
+}
+
+// failed - xatoni hisobga oladi; zaxiraga o'tilgan bo'lsa true
+// force - xatolar soniga qaramay darhol zaxiraga o'tish
+func (w *WatchdogSink) failed(err error, force bool) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	w.state.Failures++
+	w.state.LastError = err.Error()
+	w.state.LastFailure = &now
+	log.Printf("⚠️ %s xato (%d/%d): %v", w.primary.Name(), w.state.Failures, w.maxFailures, err)
+
+	if (w.state.Failures < w.maxFailures && !force) || w.state.State != DeviceOK {
+		return false
+	}
+
+	w.state.State = DeviceFallback
+	w.state.Active = w.fallback.Name()
+	w.state.Since = &now
+	log.Printf("🚨 %s ishlamayapti, e'lonlar %s ga yo'naltirildi", w.primary.Name(), w.fallback.Name())
+
+	if !w.recovering {
+		w.recovering = true
+		go w.recover()
+	}
+	return true
+}
+
+// recover - asosiy sink tiklanguncha har interval da qayta ochib ko'radi
+func (w *WatchdogSink) recover() {
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-time.After(w.interval):
+		}
+
+		if err := w.tryRecover(); err != nil {
+			log.Printf("🔌 %s hali ishlamayapti: %v", w.primary.Name(), err)
+			continue
+		}
+
+		w.mu.Lock()
+		w.state.State = DeviceOK
+		w.state.Active = w.primary.Name()
+		w.state.Failures = 0
+		w.state.Since = nil
+		w.state.Recoveries++
+		w.recovering = false
+		w.mu.Unlock()
+
+		log.Printf("✅ %s qayta ishlayapti, e'lonlar unga qaytarildi", w.primary.Name())
+		return
+	}
+}
+
+// tryRecover - qurilmani qayta ochadi va qisqa sukunat bilan tekshiradi
+func (w *WatchdogSink) tryRecover() error {
+	w.mu.Lock()
+	w.state.RecoveryAttempts++
+	format := w.format
+	w.mu.Unlock()
+
+	if r, ok := w.primary.(Reinitializer); ok {
+		if err := w.reinit(r); err != nil {
+			return err
+		}
+	}
+
+	w.mu.Lock()
+	busy := w.busy
+	w.mu.Unlock()
+	if busy {
+		return fmt.Errorf("oldingi ijro hali qaytmadi")
+	}
+	if format.SampleRate == 0 {
+		return nil
+	}
+	return w.playPrimary(silence(format, probeLength), format)
+}
+
+// reinit - Reinit ni timeout bilan chaqiradi (qotib qolgan drayver serverni to'xtatmasin)
+// Timeout bo'lsa chaqiruv tashlab ketiladi; keyingi urinishda sink o'zi
+// oldingi qayta ochish tugamaganini xato sifatida qaytaradi
+func (w *WatchdogSink) reinit(r Reinitializer) error {
+	result := make(chan error, 1)
+	go func() {
+		result <- r.Reinit()
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(reinitTimeout):
+		return fmt.Errorf("qayta ochish %v ichida tugamadi", reinitTimeout)
+	}
+}
+
+// Interrupt - faol sinkdagi ijroni to'xtatadi
+// Asosiy sink qotib qolgan bo'lsa unga tegilmaydi (Interrupt ham qotib qolishi mumkin)
+func (w *WatchdogSink) Interrupt() {
+	w.mu.Lock()
+	primaryOK := w.state.State == DeviceOK
+	w.mu.Unlock()
+
+	if primaryOK {
+		w.primary.Interrupt()
+	}
+	w.fallback.Interrupt()
+}
+
+func (w *WatchdogSink) Close() error {
+	w.stopOnce.Do(func() { close(w.stop) })
+
+	w.mu.Lock()
+	primaryOK := w.state.State == DeviceOK
+	w.mu.Unlock()
+
+	if err := w.fallback.Close(); err != nil {
+		log.Printf("⚠️ %s yopilmadi: %v", w.fallback.Name(), err)
+	}
+	if !primaryOK {
+		return nil
+	}
+	return w.primary.Close()
+}
+
+// silence - berilgan davomiylikdagi sukunat oqimi
+func silence(format beep.Format, d time.Duration) beep.StreamSeeker {
+	buffer := beep.NewBuffer(format)
+	buffer.Append(beep.Silence(format.SampleRate.N(d)))
+	return buffer.Streamer(0, buffer.Len())
+}
@@ -0,0 +1,71 @@
+package audio
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// brokenSink - uzilgan qurilma: har bir ijro xato bilan tugaydi
+type brokenSink struct{}
+
+func (brokenSink) Name() string { return SinkSpeaker }
+
+func (brokenSink) Play(stream beep.StreamSeeker, format beep.Format) error {
+	return errors.New("qurilma topilmadi")
+}
+
+func (brokenSink) Interrupt() {}
+
+func (brokenSink) Close() error { return nil }
+
+func TestWatchdogNullFallbackIsNotHeard(t *testing.T) {
+	dir := writeTestPack(t, testCallClips)
+	watchdog := NewWatchdogSink(brokenSink{}, NewNullSink(), time.Hour)
+	defer watchdog.Close()
+	service := newTestService(t, dir, watchdog)
+	req := Announcement{QueueNumber: "5", RoomNumber: "3"}
+
+	// Birinchi xato: hali zaxiraga o'tilmagan
+	_, route, err := service.playAnnouncement(watchdog, req)
+	if err == nil || route.fallback {
+		t.Fatalf("birinchi ijro: route = %+v, err = %v", route, err)
+	}
+
+	// Ikkinchi xatodan keyin null zaxiraga o'tiladi - zalda hech narsa eshitilmadi
+	for i := 0; i < 2; i++ {
+		steps, route, err := service.playAnnouncement(watchdog, req)
+		if !errors.Is(err, ErrSilentFallback) {
+			t.Fatalf("zaxiradagi ijro: err = %v, kutilgan ErrSilentFallback", err)
+		}
+		if route.sink != SinkNull || !route.fallback {
+			t.Fatalf("zaxiradagi ijro: route = %+v", route)
+		}
+		if played := playedSteps(steps); played != 0 {
+			t.Fatalf("zaxiradagi ijro: %d bosqich eshitildi deb belgilangan", played)
+		}
+	}
+}
+
+func TestWatchdogFallbackRoute(t *testing.T) {
+	dir := writeTestPack(t, testCallClips)
+	memory := NewMemorySink()
+	watchdog := NewWatchdogSink(brokenSink{}, memory, time.Hour)
+	defer watchdog.Close()
+	service := newTestService(t, dir, watchdog)
+	req := Announcement{QueueNumber: "5", RoomNumber: "3"}
+
+	service.playAnnouncement(watchdog, req)
+	steps, route, err := service.playAnnouncement(watchdog, req)
+	if err != nil {
+		t.Fatalf("zaxiradagi ijro: %v", err)
+	}
+	if route.sink != SinkMemory || !route.fallback {
+		t.Fatalf("route = %+v, kutilgan memory zaxirasi", route)
+	}
+	if playedSteps(steps) != len(steps) || len(memory.Recordings()) != 1 {
+		t.Fatalf("e'lon zaxiraga yozilmadi: %d/%d bosqich, %d yozuv", playedSteps(steps), len(steps), len(memory.Recordings()))
+	}
+}
@@ -0,0 +1,363 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// ==============================
+// OVOZ ZONALARI
+// Har bir qavat (kuchaytirgich kirishi) o'z navbati, worker i va chiqishiga ega:
+// 1-qavatdagi chaqiriq 3-qavatda eshitilmaydi va uning navbatini kutmaydi.
+// Xona qaysi zonada aytilishi sozlamalarda belgilanadi, ro'yxatda yo'q
+// xonalar standart zonaga ketadi. Umumiy xabarlar barcha zonalarga yoki
+// bitta zonaga yuborilishi mumkin.
+// ==============================
+
+// ZoneAll - barcha zonalar (umumiy xabarlar va jadvallar uchun)
+const ZoneAll = "all"
+
+// DefaultZoneName - zonalar sozlanmaganda yagona zona nomi
+const DefaultZoneName = "default"
+
+// ErrZoneNotFound - bunday zona yo'q
+var ErrZoneNotFound = errors.New("zona topilmadi")
+
+// Zones - zonalar navbatlari va xonalarning zonalarga bog'lanishi
+// Zonalar ishga tushirilgandan keyin o'zgarmaydi, shuning uchun qulf kerak emas
+type Zones struct {
+	audioService *AudioService
+	names        []string // Sozlamalardagi tartib
+	queues       map[string]*AudioQueueService
+	rooms        map[string]string // Xona -> zona
+	defaultZone  string
+	history      *History
+}
+
+// NewZones - bo'sh zonalar ro'yxati; defaultZone bo'sh bo'lsa birinchi qo'shilgan zona
+func NewZones(defaultZone string) *Zones {
+	return &Zones{
+		queues:      map[string]*AudioQueueService{},
+		rooms:       map[string]string{},
+		defaultZone: defaultZone,
+	}
+}
+
+// Add - zonani qo'shadi; navbat oldindan WithZone bilan sozlangan bo'lishi kerak
+func (z *Zones) Add(queue *AudioQueueService, rooms []string) error {
+	name := queue.Zone()
+	if name == "" || strings.EqualFold(name, ZoneAll) {
+		return fmt.Errorf("zona nomi noto'g'ri: %q", name)
+	}
+	if _, ok := z.queues[name]; ok {
+		return fmt.Errorf("zona takrorlangan: %q", name)
+	}
+	for _, room := range rooms {
+		key := zoneRoomKey(room)
+		if other, ok := z.rooms[key]; ok {
+			return fmt.Errorf("%s-xona ikki zonada: %s va %s", room, other, name)
+		}
+		z.rooms[key] = name
+	}
+
+	z.names = append(z.names, name)
+	z.queues[name] = queue
+	if z.audioService == nil {
+		z.audioService = queue.audioService
+	}
+	if z.history == nil {
+		z.history = queue.History()
+	}
+	if z.defaultZone == "" {
+		z.defaultZone = name
+	}
+	return nil
+}
+
+// Validate - standart zona mavjudligini tekshiradi (barcha zonalar qo'shilgandan keyin)
+func (z *Zones) Validate() error {
+	if len(z.names) == 0 {
+		return fmt.Errorf("birorta zona yo'q")
+	}
+	if _, ok := z.queues[z.defaultZone]; !ok {
+		return fmt.Errorf("standart zona topilmadi: %q", z.defaultZone)
+	}
+	return nil
+}
+
+// Names - zonalar nomlari, sozlamalardagi tartibda
+func (z *Zones) Names() []string {
+	return append([]string{}, z.names...)
+}
+
+// Queue - zona navbati
+func (z *Zones) Queue(name string) (*AudioQueueService, bool) {
+	queue, ok := z.queues[name]
+	return queue, ok
+}
+
+// ForRoom - xona e'lonlari aytiladigan zona navbati
+func (z *Zones) ForRoom(room string) *AudioQueueService {
+	if name, ok := z.rooms[zoneRoomKey(room)]; ok {
+		return z.queues[name]
+	}
+	return z.queues[z.defaultZone]
+}
+
+// History - barcha zonalar uchun umumiy e'lonlar tarixi
+func (z *Zones) History() *History {
+	return z.history
+}
+
+// Start - barcha zonalar navbatlarini ishga tushiradi
+func (z *Zones) Start() {
+	for _, name := range z.names {
+		z.queues[name].Start()
+	}
+	log.Printf("🔊 Ovoz zonalari: %v (standart: %s)", z.names, z.defaultZone)
+}
+
+// Stop - navbatlarni to'xtatadi va zonalarning o'z chiqishlarini yopadi
+// (AudioService chiqishi AudioService.Close da yopiladi)
+func (z *Zones) Stop() {
+	for _, name := range z.names {
+		queue := z.queues[name]
+		queue.Stop()
+		if queue.sink != nil && queue.sink != queue.audioService.sink {
+			if err := queue.sink.Close(); err != nil {
+				log.Printf("⚠️ %s zona chiqishi yopilmadi: %v", name, err)
+			}
+		}
+	}
+	// Navbatlar to'xtagach, tarixning oxirgi yozuvlari faylga saqlanadi
+	if z.history != nil {
+		z.history.Close()
+	}
+}
+
+// AddTask - chaqiriqni xona zonasi navbatiga qo'yadi
+func (z *Zones) AddTask(req Announcement) (AudioTask, error) {
+	return z.ForRoom(req.RoomNumber).AddTask(req)
+}
+
+// Repeat - e'lonni xona zonasida qayta navbatga qo'yadi
+func (z *Zones) Repeat(req Announcement) (AudioTask, error) {
+	return z.ForRoom(req.RoomNumber).Repeat(req)
+}
+
+// Broadcast - umumiy xabar: zone bo'sh yoki "all" bo'lsa barcha zonalarga
+func (z *Zones) Broadcast(zone string, items []string, preempt bool) ([]AudioTask, error) {
+	queues, err := z.targets([]string{zone})
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]AudioTask, 0, len(queues))
+	for _, queue := range queues {
+		task, err := queue.Broadcast(items, preempt)
+		if err != nil {
+			return tasks, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// Scheduled - jadval bo'yicha xabar: zones bo'sh bo'lsa barcha zonalarga
+func (z *Zones) Scheduled(zones []string, scheduleID string, items []string, preempt bool) ([]AudioTask, error) {
+	queues, err := z.targets(zones)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]AudioTask, 0, len(queues))
+	for _, queue := range queues {
+		task, err := queue.Scheduled(scheduleID, items, preempt)
+		if err != nil {
+			return tasks, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// CheckZones - nomlar mavjudligini tekshiradi ("all" va bo'sh ro'yxat ham to'g'ri)
+func (z *Zones) CheckZones(zones []string) error {
+	_, err := z.targets(zones)
+	return err
+}
+
+// targets - nomlar bo'yicha navbatlar; bo'sh yoki "all" - barchasi
+func (z *Zones) targets(zones []string) ([]*AudioQueueService, error) {
+	all := len(zones) == 0
+	for _, name := range zones {
+		if name == "" || strings.EqualFold(name, ZoneAll) {
+			all = true
+		} else if _, ok := z.queues[name]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrZoneNotFound, name)
+		}
+	}
+	if all {
+		zones = z.names
+	}
+
+	seen := map[string]bool{}
+	queues := make([]*AudioQueueService, 0, len(zones))
+	for _, name := range zones {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		queues = append(queues, z.queues[name])
+	}
+	return queues, nil
+}
+
+// find - task qaysi zona navbatida
+func (z *Zones) find(id string) (*AudioQueueService, AudioTask, bool) {
+	for _, name := range z.names {
+		if task, ok := z.queues[name].Task(id); ok {
+			return z.queues[name], task, true
+		}
+	}
+	return nil, AudioTask{}, false
+}
+
+// Task - task holati ID bo'yicha (barcha zonalarda)
+func (z *Zones) Task(id string) (AudioTask, bool) {
+	_, task, ok := z.find(id)
+	return task, ok
+}
+
+// Wait - task tugashini kutadi
+func (z *Zones) Wait(ctx context.Context, id string) (AudioTask, error) {
+	queue, _, ok := z.find(id)
+	if !ok {
+		return AudioTask{}, ErrTaskNotFound
+	}
+	return queue.Wait(ctx, id)
+}
+
+// Cancel - kutayotgan taskni bekor qiladi
+func (z *Zones) Cancel(id string) (AudioTask, bool) {
+	queue, _, ok := z.find(id)
+	if !ok {
+		return AudioTask{}, false
+	}
+	return queue.Cancel(id)
+}
+
+// MoveToFront - kutayotgan taskni o'z zonasi navbati boshiga o'tkazadi
+func (z *Zones) MoveToFront(id string) (AudioTask, bool) {
+	queue, _, ok := z.find(id)
+	if !ok {
+		return AudioTask{}, false
+	}
+	return queue.MoveToFront(id)
+}
+
+// Pending - barcha zonalarda kutayotgan tasklar (zona tartibida)
+func (z *Zones) Pending() []AudioTask {
+	tasks := []AudioTask{}
+	for _, name := range z.names {
+		tasks = append(tasks, z.queues[name].Pending()...)
+	}
+	return tasks
+}
+
+// Current - barcha zonalarda hozir ijro etilayotgan tasklar
+func (z *Zones) Current() []AudioTask {
+	tasks := []AudioTask{}
+	for _, name := range z.names {
+		tasks = append(tasks, z.queues[name].Current()...)
+	}
+	return tasks
+}
+
+// Stats - barcha zonalar natijalari yig'indisi
+func (z *Zones) Stats() TaskStats {
+	var total TaskStats
+	for _, name := range z.names {
+		stats := z.queues[name].Stats()
+		total.Played += stats.Played
+		total.Partial += stats.Partial
+		total.Failed += stats.Failed
+		total.Dropped += stats.Dropped
+		total.Cancelled += stats.Cancelled
+	}
+	return total
+}
+
+// ClearQueue - barcha zonalar navbatlarini tozalaydi
+func (z *Zones) ClearQueue() int {
+	count := 0
+	for _, name := range z.names {
+		count += z.queues[name].ClearQueue()
+	}
+	return count
+}
+
+// Status - bitta zona holati
+func (z *Zones) Status(name string) (map[string]interface{}, bool) {
+	queue, ok := z.queues[name]
+	if !ok {
+		return nil, false
+	}
+	status := queue.GetStatus()
+	status["default"] = name == z.defaultZone
+	status["rooms"] = z.zoneRooms(name)
+	return status, true
+}
+
+// GetStatus - umumiy holat va har bir zona holati
+func (z *Zones) GetStatus() map[string]interface{} {
+	running := true
+	queueLength, playing, workers := 0, 0, 0
+	zones := make([]map[string]interface{}, 0, len(z.names))
+	for _, name := range z.names {
+		status, _ := z.Status(name)
+		running = running && status["is_running"].(bool)
+		queueLength += status["queue_length"].(int)
+		playing += status["playing"].(int)
+		workers += status["worker_count"].(int)
+		zones = append(zones, status)
+	}
+
+	return map[string]interface{}{
+		"is_running":   running,
+		"queue_length": queueLength,
+		"playing":      playing,
+		"worker_count": workers,
+		"default_zone": z.defaultZone,
+		"zones":        zones,
+		"tasks":        z.Stats(),
+	}
+}
+
+// DeviceStates - har bir zona chiqish qurilmasi holati
+func (z *Zones) DeviceStates() map[string]DeviceState {
+	states := make(map[string]DeviceState, len(z.names))
+	for _, name := range z.names {
+		states[name] = z.queues[name].DeviceState()
+	}
+	return states
+}
+
+// zoneRooms - zonaga bog'langan xonalar
+func (z *Zones) zoneRooms(name string) []string {
+	rooms := []string{}
+	for room, zone := range z.rooms {
+		if zone == name {
+			rooms = append(rooms, room)
+		}
+	}
+	sort.Strings(rooms)
+	return rooms
+}
+
+// zoneRoomKey - "316-xona", "316" va " 316 " bir xil xona
+func zoneRoomKey(room string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(room), "-xona"))
+}
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ==============================
+// AUDIO SOZLAMALARI
+// ==============================
+
+const (
+	DefaultSoundsDir       = "./sounds"
+	DefaultAudioSampleRate = 44100  // Barcha kliplar shu chastotaga keltiriladi
+	DefaultAudioGapMs      = 150    // E'lon bosqichlari orasidagi sukunat
+	DefaultAudioCrossfade  = 15     // Bir bosqich ichidagi so'zlar ulanishi (ms)
+	DefaultAudioCacheMB    = 64     // Dekodlangan kliplar keshi chegarasi
+	DefaultAudioRenderKeep = 200    // Brauzerlar uchun saqlanadigan e'lonlar
+	DefaultAudioDedupSec   = 10     // Bir chipta uchun takroriy e'lonlar birlashtiriladigan oraliq
+	DefaultAudioHistory    = 1000   // <data_dir>/audio-history.json dagi yozuvlar
+	DefaultFallbackSink    = "null" // Qurilma ishlamay qolganda e'lonlar shu yerga ketadi
+	DefaultAudioRecovery   = 30     // Ishlamay qolgan qurilmani qayta ochish oralig'i (soniya)
+	DefaultAudioTrimPadMs  = 30     // Sukunat kesilganda qoldiriladigan zaxira
+	DefaultAudioMaxGainDB  = 12     // Sokin klipni ko'pi bilan shuncha kuchaytirish
+)
+
+// AudioSettings - e'lon ovozini yig'ish sozlamalari
+type AudioSettings struct {
+	SoundsDir   string `json:"sounds_dir"`
+	SampleRate  int    `json:"sample_rate"`  // Chiqish chastotasi (Hz)
+	GapMs       int    `json:"gap_ms"`       // "yigirma besh" | pauza | "raqam egasi"
+	CrossfadeMs int    `json:"crossfade_ms"` // "yigirma" va "besh" orasidagi silliq o'tish
+	CacheMB     int    `json:"cache_mb"`     // Xotiradagi kliplar uchun chegara
+	Language    string `json:"language"`     // sounds/manifest.json dagi til paketi, bo'sh - standart
+
+	// Sink - e'lon qayerga chiqariladi: "speaker" (standart), "wav", "null", "memory"
+	// Ovoz kartasi yo'q server yoki CI uchun "wav"/"null"
+	Sink    string `json:"sink"`
+	SinkDir string `json:"sink_dir"` // "wav" sink fayllari, bo'sh bo'lsa <data_dir>/audio-out
+
+	// FallbackSink - asosiy chiqish ketma-ket ishlamasa (quloqchin uzilgan, RDP),
+	// qurilma qayta ochilguncha e'lonlar shu yerga ketadi: "null" yoki "wav"
+	FallbackSink string `json:"fallback_sink"`
+	RecoverySec  int    `json:"recovery_sec"` // Qurilmani qayta ochish urinishlari oralig'i
+
+	// Zones - qavatlar (kuchaytirgich kirishlari): har birining o'z navbati va chiqishi
+	//   [{"name": "1-qavat", "sink": "device", "device": "Speakers (Realtek", "rooms": ["101", "102"]},
+	//    {"name": "2-qavat", "sink": "device", "device": "USB Audio", "rooms": ["201", "204"]}]
+	// Bo'sh bo'lsa bitta "default" zona (audio.sink). Ro'yxatda yo'q xonalar default_zone da aytiladi
+	Zones       []ZoneSettings `json:"zones,omitempty"`
+	DefaultZone string         `json:"default_zone,omitempty"` // Bo'sh - birinchi zona
+
+	// DisplayAudio - har bir chaqiriq WAV ga render qilinadi va zal ekranlariga
+	// audio_url yuboriladi (kiosk kompyuteriga ulanmagan televizorlar uchun)
+	DisplayAudio bool `json:"display_audio"`
+	RenderKeep   int  `json:"render_keep"` // <data_dir>/announcements da saqlanadigan fayllar soni
+
+	// DedupSec - shu oraliqda bir chipta qayta chaqirilsa, e'lon takrorlanmaydi (0 - o'chirilgan)
+	DedupSec int `json:"dedup_sec"`
+
+	// HistoryKeep - saqlanadigan e'lonlar tarixi (qayta e'lon va shikoyatlarni tekshirish uchun)
+	HistoryKeep int `json:"history_keep"`
+
+	// Templates - e'lon ketma-ketliklari, masalan
+	//   "ikki_tilli": ["{number}", "phrases/raqam_egasi", "{room}", "phrases/honaga_kelishin",
+	//                  "ru:phrases/smotrite_tablo"]
+	// Bo'lim yoki shifokor "template" maydoni orqali tanlaydi
+	// {number} va {room} faqat o'zbek paketida yig'iladi: "ru:{number}" xato hisoblanadi
+	Templates       map[string][]string `json:"templates,omitempty"`
+	DefaultTemplate string              `json:"default_template,omitempty"` // Bo'sh - "default"
+
+	TTS TTSSettings `json:"tts"`
+
+	// Preprocess - kliplarni yuklashda boshidagi/oxiridagi sukunatni kesish va
+	// balandlikni tenglash (bir marta WAV paketga yozish uchun: normalize-pack)
+	Preprocess PreprocessSettings `json:"preprocess"`
+
+	// Messages - umumiy xabarlar: ID -> tokenlar, POST /api/audio/broadcast va jadvallarda message_id bilan
+	//   "yopilish_15": ["phrases/klinika_15_daqiqada_yopiladi", "ru:phrases/klinika_15_daqiqada_yopiladi"]
+	Messages map[string][]string `json:"messages,omitempty"`
+}
+
+// ZoneSettings - bitta ovoz zonasi
+// speaker butun dasturda bitta (standart qurilma), uni faqat bitta zona ishlata oladi.
+// Har bir qavat o'z kuchaytirgichiga chiqishi uchun "device" sink va qurilma
+// nomi yoki indeksi beriladi (Windows)
+type ZoneSettings struct {
+	Name    string   `json:"name"`
+	Sink    string   `json:"sink"`             // speaker, device, wav, null; bo'sh - audio.sink
+	Device  string   `json:"device,omitempty"` // device sink: qurilma nomi (bir qismi) yoki indeksi
+	SinkDir string   `json:"sink_dir"`         // wav fayllari, bo'sh bo'lsa <audio.sink_dir>/<name>
+	Rooms   []string `json:"rooms"`            // Shu zonada aytiladigan xonalar
+}
+
+// ZoneList - sozlangan zonalar; bo'sh bo'lsa audio.sink dagi bitta "default" zona
+func (a AudioSettings) ZoneList() []ZoneSettings {
+	if len(a.Zones) == 0 {
+		return []ZoneSettings{{Name: "default", Sink: a.Sink, SinkDir: a.SinkDir}}
+	}
+	return a.Zones
+}
+
+// PreprocessSettings - kliplarni tozalash; trim_db va target_db 0 bo'lsa o'chirilgan
+type PreprocessSettings struct {
+	TrimDB    float64 `json:"trim_db"`     // Shundan past sukunat kesiladi (dBFS), masalan -45
+	TrimPadMs int     `json:"trim_pad_ms"` // Kesilgan joyda qoldiriladigan sukunat
+	TargetDB  float64 `json:"target_db"`   // Nutq RMS darajasi (dBFS), masalan -20
+	MaxGainDB float64 `json:"max_gain_db"` // Kuchaytirish chegarasi (shovqin ko'tarilmasligi uchun)
+}
+
+// TrimPad - kesilgan joyda qoldiriladigan sukunat
+func (p PreprocessSettings) TrimPad() time.Duration {
+	return time.Duration(p.TrimPadMs) * time.Millisecond
+}
+
+// TTSSettings - yozuvi yo'q tokenlar uchun mahalliy TTS dasturi
+// Command bo'sh bo'lsa, TTS o'chirilgan
+type TTSSettings struct {
+	Command    string   `json:"command"`     // "espeak-ng" yoki "piper"
+	Args       []string `json:"args"`        // {text}, {out}, {lang} o'rniga qo'yiladi
+	CacheDir   string   `json:"cache_dir"`   // Bo'sh bo'lsa <data_dir>/tts-cache
+	TimeoutSec int      `json:"timeout_sec"` // Bitta ibora uchun, 0 - 30 soniya
+}
+
+// Timeout - TTS dasturi kutiladigan vaqt
+func (t TTSSettings) Timeout() time.Duration {
+	return time.Duration(t.TimeoutSec) * time.Second
+}
+
+// DefaultAudioSettings - standart audio sozlamalari
+func DefaultAudioSettings() AudioSettings {
+	return AudioSettings{
+		SoundsDir:    DefaultSoundsDir,
+		SampleRate:   DefaultAudioSampleRate,
+		GapMs:        DefaultAudioGapMs,
+		CrossfadeMs:  DefaultAudioCrossfade,
+		CacheMB:      DefaultAudioCacheMB,
+		RenderKeep:   DefaultAudioRenderKeep,
+		DedupSec:     DefaultAudioDedupSec,
+		HistoryKeep:  DefaultAudioHistory,
+		FallbackSink: DefaultFallbackSink,
+		RecoverySec:  DefaultAudioRecovery,
+		Preprocess: PreprocessSettings{
+			TrimPadMs: DefaultAudioTrimPadMs,
+			MaxGainDB: DefaultAudioMaxGainDB,
+		},
+	}
+}
+
+// Gap - bosqichlar orasidagi pauza
+func (a AudioSettings) Gap() time.Duration {
+	return time.Duration(a.GapMs) * time.Millisecond
+}
+
+// Crossfade - so'zlar orasidagi o'tish davomiyligi
+func (a AudioSettings) Crossfade() time.Duration {
+	return time.Duration(a.CrossfadeMs) * time.Millisecond
+}
+
+// DedupWindow - takroriy e'lonlar birlashtiriladigan oraliq
+func (a AudioSettings) DedupWindow() time.Duration {
+	return time.Duration(a.DedupSec) * time.Second
+}
+
+// RecoveryInterval - ishlamay qolgan qurilmani qayta ochish oralig'i
+func (a AudioSettings) RecoveryInterval() time.Duration {
+	return time.Duration(a.RecoverySec) * time.Second
+}
+
+// CacheBytes - kesh chegarasi baytlarda
+func (a AudioSettings) CacheBytes() int64 {
+	return int64(a.CacheMB) << 20
+}
+
+// normalize - noto'g'ri qiymatlarni standartga qaytaradi
+func (a *AudioSettings) normalize() {
+	if a.SoundsDir == "" {
+		a.SoundsDir = DefaultSoundsDir
+	}
+	if a.SampleRate <= 0 {
+		a.SampleRate = DefaultAudioSampleRate
+	}
+	if a.CacheMB <= 0 {
+		a.CacheMB = DefaultAudioCacheMB
+	}
+	if a.RenderKeep <= 0 {
+		a.RenderKeep = DefaultAudioRenderKeep
+	}
+	if a.GapMs < 0 {
+		a.GapMs = 0
+	}
+	if a.CrossfadeMs < 0 {
+		a.CrossfadeMs = 0
+	}
+	if a.HistoryKeep <= 0 {
+		a.HistoryKeep = DefaultAudioHistory
+	}
+	if a.DedupSec < 0 {
+		a.DedupSec = 0
+	}
+	if a.FallbackSink == "" {
+		a.FallbackSink = DefaultFallbackSink
+	}
+	if a.RecoverySec <= 0 {
+		a.RecoverySec = DefaultAudioRecovery
+	}
+	if a.Preprocess.TrimPadMs < 0 {
+		a.Preprocess.TrimPadMs = 0
+	}
+	for i := range a.Zones {
+		zone := &a.Zones[i]
+		zone.Name = strings.TrimSpace(zone.Name)
+		if zone.Sink == "" {
+			zone.Sink = a.Sink
+		}
+		if zone.SinkDir == "" {
+			zone.SinkDir = filepath.Join(a.SinkDir, zone.Name)
+		}
+		for j := range zone.Rooms {
+			zone.Rooms[j] = NormalizeRoom(zone.Rooms[j])
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// ============================================
+// FAYL ORQALI SOZLAMALAR - KASALXONA PRINTER TIZIMI
+// config.json faylidan o'qiladigan, ish vaqtida o'zgaradigan sozlamalar
+// ============================================
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ==============================
+// STANDART QIYMATLAR
+// ==============================
+
+const (
+	// DefaultSettingsPath - sozlamalar fayli yo'li
+	// POS80_CONFIG environment variable orqali o'zgartirish mumkin
+	DefaultSettingsPath = "config.json"
+
+	// DefaultDataDir - navbat, smena va tarix ma'lumotlari saqlanadigan papka
+	DefaultDataDir = "data"
+)
+
+// ==============================
+// SOZLAMALAR STRUKTURASI
+// ==============================
+
+// Settings - config.json faylidagi barcha sozlamalar
+// Fayl bo'lmasa, standart qiymatlar ishlatiladi
+type Settings struct {
+	// DataDir - smena va chiptalar holati saqlanadigan papka
+	DataDir string `json:"data_dir"`
+
+	// WebDir - kiosk va tablo sahifalarini diskdan almashtirish uchun papka
+	// Bo'sh bo'lsa yoki faylda yo'q bo'lsa, binary ichidagi sahifalar ishlatiladi
+	WebDir string `json:"web_dir"`
+
+	// PublicBaseURL - bemorlar telefonidan ochiladigan server manzili
+	// Chiptadagi QR kod "<public_base_url>/t/<ticket_id>" sahifasiga olib boradi
+	// Misol: "http://192.168.100.86:8080"
+	PublicBaseURL string `json:"public_base_url"`
+
+	// Departments va Doctors - kiosk tugmalari uchun ma'lumotnoma
+	Departments []DepartmentSettings `json:"departments"`
+	Doctors     []DoctorSettings     `json:"doctors"`
+
+	// Rooms - xonalar va shifokor paneliga kirish PIN kodlari
+	Rooms []RoomSettings `json:"rooms"`
+
+	// Audio - ovozli e'lon sozlamalari
+	Audio AudioSettings `json:"audio"`
+}
+
+// DepartmentSettings - bo'lim ma'lumotlari
+type DepartmentSettings struct {
+	Name   string `json:"name"`   // Bo'lim nomi: "Kardiologiya"
+	Prefix string `json:"prefix"` // Navbat raqami harfi: "K"
+
+	Template string `json:"template,omitempty"` // audio.templates dagi e'lon shabloni, bo'sh - standart
+}
+
+// DoctorSettings - shifokor ma'lumotlari
+// qo'llanma.json dagi shifokorlar ro'yxati bilan bir xil format
+type DoctorSettings struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Specialization string `json:"specialization"`
+	Room           string `json:"room"`               // "316" yoki "316-xona"
+	Department     string `json:"department"`         // Bo'sh bo'lsa, mutaxassislik bo'lim sifatida ishlatiladi
+	Template       string `json:"template,omitempty"` // Bo'limdagidan boshqa e'lon shabloni kerak bo'lsa
+}
+
+// RoomSettings - xona sozlamalari
+type RoomSettings struct {
+	Number string `json:"number"` // Xona raqami: "204"
+	PIN    string `json:"pin"`    // Shifokor paneli uchun PIN yoki kalit
+}
+
+// FindRoom - xonani raqami bo'yicha topadi
+func (s *Settings) FindRoom(number string) (RoomSettings, bool) {
+	number = NormalizeRoom(number)
+	for _, room := range s.Rooms {
+		if strings.EqualFold(room.Number, number) {
+			return room, true
+		}
+	}
+	return RoomSettings{}, false
+}
+
+// RoomNumbers - ma'lumotnomadagi barcha xonalar (xonalar va shifokorlar bo'yicha, takrorlanmasdan)
+func (s *Settings) RoomNumbers() []string {
+	var rooms []string
+	seen := map[string]bool{}
+	add := func(number string) {
+		key := strings.ToLower(number)
+		if number == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		rooms = append(rooms, number)
+	}
+
+	for _, room := range s.Rooms {
+		add(room.Number)
+	}
+	for _, doctor := range s.Doctors {
+		add(doctor.Room)
+	}
+	return rooms
+}
+
+// DepartmentNames - barcha bo'limlar (bo'limlar va shifokorlar bo'yicha, takrorlanmasdan)
+func (s *Settings) DepartmentNames() []string {
+	var names []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		names = append(names, name)
+	}
+
+	for _, department := range s.Departments {
+		add(department.Name)
+	}
+	for _, doctor := range s.Doctors {
+		if doctor.Department != "" {
+			add(doctor.Department)
+		} else {
+			add(doctor.Specialization)
+		}
+	}
+	return names
+}
+
+// FindDoctor - shifokorni ID bo'yicha topadi
+func (s *Settings) FindDoctor(id string) (DoctorSettings, bool) {
+	for _, doctor := range s.Doctors {
+		if doctor.ID == id {
+			return doctor, true
+		}
+	}
+	return DoctorSettings{}, false
+}
+
+// FindDepartment - bo'limni nomi bo'yicha topadi (katta-kichik harf farqi yo'q)
+func (s *Settings) FindDepartment(name string) (DepartmentSettings, bool) {
+	for _, department := range s.Departments {
+		if strings.EqualFold(department.Name, name) {
+			return department, true
+		}
+	}
+	return DepartmentSettings{}, false
+}
+
+// DefaultSettings - standart sozlamalarni qaytaradi
+func DefaultSettings() *Settings {
+	audio := DefaultAudioSettings()
+	audio.SinkDir = filepath.Join(DefaultDataDir, "audio-out")
+	audio.TTS.CacheDir = filepath.Join(DefaultDataDir, "tts-cache")
+
+	return &Settings{
+		DataDir: DefaultDataDir,
+		Audio:   audio,
+	}
+}
+
+// SettingsPath - sozlamalar fayli yo'lini aniqlaydi
+func SettingsPath() string {
+	if path := os.Getenv("POS80_CONFIG"); path != "" {
+		return path
+	}
+	return DefaultSettingsPath
+}
+
+// LoadSettings - sozlamalarni fayldan o'qiydi
+// Fayl topilmasa xato qaytarilmaydi, standart sozlamalar ishlatiladi
+func LoadSettings(path string) (*Settings, error) {
+	settings := DefaultSettings()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		log.Printf("⚠️ Sozlamalar fayli topilmadi (%s), standart qiymatlar ishlatiladi", path)
+		return settings, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sozlamalar faylini o'qib bo'lmadi: %w", err)
+	}
+
+	if err := json.Unmarshal(data, settings); err != nil {
+		return nil, fmt.Errorf("sozlamalar fayli noto'g'ri: %w", err)
+	}
+
+	if settings.DataDir == "" {
+		settings.DataDir = DefaultDataDir
+	}
+	if settings.Audio.SinkDir == "" {
+		settings.Audio.SinkDir = filepath.Join(settings.DataDir, "audio-out")
+	}
+	if settings.Audio.TTS.CacheDir == "" {
+		settings.Audio.TTS.CacheDir = filepath.Join(settings.DataDir, "tts-cache")
+	}
+
+	settings.normalize()
+	return settings, nil
+}
+
+// normalize - ma'lumotnomadagi qiymatlarni bir xil ko'rinishga keltiradi
+func (s *Settings) normalize() {
+	for i := range s.Doctors {
+		doctor := &s.Doctors[i]
+		doctor.Room = NormalizeRoom(doctor.Room)
+		if doctor.Department == "" {
+			doctor.Department = doctor.Specialization
+		}
+	}
+	for i := range s.Rooms {
+		s.Rooms[i].Number = NormalizeRoom(s.Rooms[i].Number)
+	}
+	for i := range s.Departments {
+		s.Departments[i].Prefix = strings.ToUpper(strings.TrimSpace(s.Departments[i].Prefix))
+	}
+	s.Audio.normalize()
+}
+
+// NormalizeRoom - "316-xona" -> "316"
+func NormalizeRoom(room string) string {
+	room = strings.TrimSpace(room)
+	return strings.TrimSuffix(room, "-xona")
+}
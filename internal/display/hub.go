@@ -0,0 +1,306 @@
+// ============================================
+// DISPLAY HUB - ZALDAGI EKRAN UCHUN JONLI OQIM
+// Chaqirilgan raqamlar va xonalar haqidagi hodisalarni ulangan ekranlarga tarqatadi
+// ============================================
+
+package display
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==============================
+// HODISA TURLARI
+// ==============================
+
+const (
+	EventCalled    = "called"    // Raqam birinchi marta chaqirildi
+	EventRecalled  = "recalled"  // Raqam qayta chaqirildi
+	EventCompleted = "completed" // Qabul tugadi
+	EventIssued    = "issued"    // Xona navbatiga yangi chipta qo'shildi
+	EventStarted   = "started"   // Bemor qabulga kirdi
+	EventSkipped   = "skipped"   // Bemor chaqiruvga kelmadi
+	EventAudio     = "audio"     // Chaqiriq e'loni brauzer uchun render qilindi (audio_url)
+)
+
+const (
+	// DefaultRoomHistory - har bir xona uchun xotirada saqlanadigan oxirgi chaqiriqlar soni
+	DefaultRoomHistory = 20
+
+	// subscriberBuffer - sekin mijoz butun hubni to'xtatib qo'ymasligi uchun bufer
+	subscriberBuffer = 32
+)
+
+// Event - ekranga yuboriladigan bitta hodisa
+// TicketID faqat server ichida (qayta chaqiriqni aniqlash, e'lon): /t/:ticket_id sahifasi
+// shu ID bilan ochiladi, shuning uchun u ommaviy oqim va snapshotga chiqmaydi
+type Event struct {
+	ID             uint64    `json:"id"`
+	Type           string    `json:"type"`
+	TicketID       string    `json:"-"`
+	QueueNumber    string    `json:"queue_number"`
+	RoomNumber     string    `json:"room_number"`
+	DepartmentName string    `json:"department_name,omitempty"`
+	DoctorID       string    `json:"doctor_id,omitempty"`
+	AudioURL       string    `json:"audio_url,omitempty"` // Brauzerda ijro etish uchun render qilingan e'lon
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// ==============================
+// HUB STRUKTURASI
+// ==============================
+
+// Hub - hodisalarni obunachilarga tarqatadi va har bir xonaning
+// oxirgi chaqiriqlarini snapshot uchun saqlaydi
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]struct{}
+	rooms       map[string]*roomCalls
+	roomHistory int
+	lastID      uint64
+}
+
+// roomCalls - xonaning oxirgi chaqiriqlari va ularning yakuni
+// Boshqa hodisalar (issued, started) bu yerga yozilmaydi: ular ko'p bo'lsa ham
+// chaqiriqlarni siqib chiqarmaydi va qayta chaqiriqni aniqlash buzilmaydi
+type roomCalls struct {
+	calls    []Event          // called, recalled - eng yangisi oxirida
+	outcomes map[string]Event // Chipta IDsi -> oxirgi completed/skipped (faqat calls dagi chiptalar)
+}
+
+// isCall - xona tarixiga yoziladigan chaqiriq hodisasimi
+func isCall(eventType string) bool {
+	return eventType == EventCalled || eventType == EventRecalled
+}
+
+// isOutcome - chaqiriq yakuni (ekrandan olib tashlanadi, keyingi chaqiriq yangi hisoblanadi)
+func isOutcome(eventType string) bool {
+	return eventType == EventCompleted || eventType == EventSkipped
+}
+
+// NewHub - yangi display hub yaratadi
+func NewHub(roomHistory int) *Hub {
+	if roomHistory <= 0 {
+		roomHistory = DefaultRoomHistory
+	}
+	return &Hub{
+		subscribers: make(map[chan Event]struct{}),
+		rooms:       make(map[string]*roomCalls),
+		roomHistory: roomHistory,
+	}
+}
+
+// Subscribe - yangi obunachi qo'shadi
+// Qaytarilgan cancel funksiyasi ulanish yopilganda chaqirilishi shart
+func (h *Hub) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	count := len(h.subscribers)
+	h.mu.Unlock()
+
+	log.Printf("📺 Ekran ulandi (jami: %d)", count)
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, ch)
+			count := len(h.subscribers)
+			h.mu.Unlock()
+			close(ch)
+			log.Printf("📺 Ekran uzildi (jami: %d)", count)
+		})
+	}
+
+	return ch, cancel
+}
+
+// PublishCall - chaqiriq hodisasini yuboradi
+// Agar chipta shu xonada avval chaqirilgan bo'lsa, hodisa "recalled" bo'ladi
+// Chipta IDsi bo'lmasa, chaqiriqni avvalgisi bilan solishtirib bo'lmaydi: doim "called"
+func (h *Hub) PublishCall(event Event) Event {
+	event.Type = EventCalled
+	if event.TicketID == "" {
+		return h.Publish(event)
+	}
+
+	h.mu.RLock()
+	if room := h.rooms[event.RoomNumber]; room != nil {
+		for i := len(room.calls) - 1; i >= 0; i-- {
+			prev := room.calls[i]
+			if prev.TicketID != event.TicketID {
+				continue
+			}
+			// Yakunlangan chipta qayta chaqirilsa, bu yangi chaqiriq hisoblanadi
+			if outcome, ok := room.outcomes[event.TicketID]; !ok || outcome.ID < prev.ID {
+				event.Type = EventRecalled
+			}
+			break
+		}
+	}
+	h.mu.RUnlock()
+
+	return h.Publish(event)
+}
+
+// Publish - hodisani barcha obunachilarga yuboradi
+// Chaqiriqlar va ularning yakuni xona tarixiga yoziladi, qolganlari faqat tarqatiladi
+// Bufer to'lgan obunachi bu hodisani o'tkazib yuboradi, boshqalar kutib qolmaydi
+func (h *Hub) Publish(event Event) Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	event.ID = h.lastID
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	switch {
+	case isCall(event.Type):
+		h.addCallLocked(event)
+	case isOutcome(event.Type):
+		h.addOutcomeLocked(event)
+	}
+
+	h.sendLocked(event)
+	return event
+}
+
+// addCallLocked - chaqiriqni xona tarixiga qo'shadi, eskilarini va ularning yakunini o'chiradi
+func (h *Hub) addCallLocked(event Event) {
+	room := h.rooms[event.RoomNumber]
+	if room == nil {
+		room = &roomCalls{outcomes: make(map[string]Event)}
+		h.rooms[event.RoomNumber] = room
+	}
+
+	room.calls = append(room.calls, event)
+	if over := len(room.calls) - h.roomHistory; over > 0 {
+		room.calls = append([]Event(nil), room.calls[over:]...)
+	}
+
+	for ticketID := range room.outcomes {
+		if !room.hasTicket(ticketID) {
+			delete(room.outcomes, ticketID)
+		}
+	}
+}
+
+// addOutcomeLocked - chaqirilgan chiptaning yakunini yozadi
+// Tarixda yo'q chipta uchun saqlanmaydi: ekranda ham, qayta chaqiriqda ham kerak emas
+func (h *Hub) addOutcomeLocked(event Event) {
+	room := h.rooms[event.RoomNumber]
+	if room == nil || event.TicketID == "" || !room.hasTicket(event.TicketID) {
+		return
+	}
+	room.outcomes[event.TicketID] = event
+}
+
+func (r *roomCalls) hasTicket(ticketID string) bool {
+	for _, call := range r.calls {
+		if call.TicketID == ticketID {
+			return true
+		}
+	}
+	return false
+}
+
+// Notify - hodisani faqat ulangan ekranlarga yuboradi, xona tarixiga yozmaydi
+// (audio_url kabi vaqtinchalik xabarlar snapshot va qayta chaqiriqni aniqlashga ta'sir qilmaydi)
+func (h *Hub) Notify(event Event) Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	event.ID = h.lastID
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	h.sendLocked(event)
+	return event
+}
+
+// sendLocked - hodisani obunachilarga tarqatadi (mu ushlangan bo'lishi kerak)
+func (h *Hub) sendLocked(event Event) {
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("⚠️ Ekran bufer to'la, hodisa o'tkazib yuborildi: %d", event.ID)
+		}
+	}
+}
+
+// Snapshot - har bir xonaning oxirgi limit ta chaqirig'i va ularning yakuni (eng yangisi birinchi)
+// room bo'sh bo'lmasa, faqat shu xona qaytariladi
+func (h *Hub) Snapshot(room string, limit int) map[string][]Event {
+	if limit <= 0 || limit > h.roomHistory {
+		limit = h.roomHistory
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	result := make(map[string][]Event)
+	for roomNumber, calls := range h.rooms {
+		if room != "" && !strings.EqualFold(roomNumber, room) {
+			continue
+		}
+		result[roomNumber] = calls.events(limit)
+	}
+
+	return result
+}
+
+// events - oxirgi limit ta chaqiriq va shu chiptalarning yakuni, ID bo'yicha eng yangisi birinchi
+func (r *roomCalls) events(limit int) []Event {
+	count := min(limit, len(r.calls))
+	events := make([]Event, 0, count)
+	seen := make(map[string]bool)
+	for i := len(r.calls) - 1; i >= len(r.calls)-count; i-- {
+		call := r.calls[i]
+		events = append(events, call)
+		if outcome, ok := r.outcomes[call.TicketID]; ok && !seen[call.TicketID] {
+			seen[call.TicketID] = true
+			events = append(events, outcome)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID > events[j].ID
+	})
+	return events
+}
+
+// Latest - barcha xonalar bo'yicha oxirgi limit ta chaqiriq va yakun (eng yangisi birinchi)
+func (h *Hub) Latest(limit int) []Event {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var events []Event
+	for _, room := range h.rooms {
+		events = append(events, room.events(h.roomHistory)...)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID > events[j].ID
+	})
+
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events
+}
+
+// SubscriberCount - hozir ulangan ekranlar soni
+func (h *Hub) SubscriberCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers)
+}
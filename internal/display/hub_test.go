@@ -0,0 +1,79 @@
+package display
+
+import "testing"
+
+func call(ticketID, number string) Event {
+	return Event{TicketID: ticketID, QueueNumber: number, RoomNumber: "204"}
+}
+
+func TestPublishCallRecall(t *testing.T) {
+	hub := NewHub(3)
+
+	if got := hub.PublishCall(call("t1", "A001")).Type; got != EventCalled {
+		t.Fatalf("birinchi chaqiriq = %s", got)
+	}
+	if got := hub.PublishCall(call("t1", "A001")).Type; got != EventRecalled {
+		t.Fatalf("qayta chaqiriq = %s", got)
+	}
+
+	// Yakunlangan chipta yana chaqirilsa - yangi chaqiriq
+	done := call("t1", "A001")
+	done.Type = EventCompleted
+	hub.Publish(done)
+	if got := hub.PublishCall(call("t1", "A001")).Type; got != EventCalled {
+		t.Fatalf("yakundan keyingi chaqiriq = %s", got)
+	}
+
+	// Chipta IDsisiz chaqiriqlar bir-biriga bog'lanmaydi
+	hub.PublishCall(call("", "A002"))
+	if got := hub.PublishCall(call("", "A003")).Type; got != EventCalled {
+		t.Fatalf("chipta IDsisiz ikkinchi chaqiriq = %s", got)
+	}
+}
+
+func TestNonCallEventsKeepCalls(t *testing.T) {
+	hub := NewHub(3)
+	hub.PublishCall(call("t1", "A001"))
+
+	// Xonaga ko'p chipta berilsa ham chaqiriq tarixdan chiqib ketmaydi
+	for i := 0; i < 10; i++ {
+		issued := call("new", "A100")
+		issued.Type = EventIssued
+		hub.Publish(issued)
+	}
+	if got := hub.PublishCall(call("t1", "A001")).Type; got != EventRecalled {
+		t.Fatalf("qayta chaqiriq = %s", got)
+	}
+
+	events := hub.Snapshot("204", 0)["204"]
+	if len(events) != 2 {
+		t.Fatalf("snapshot = %+v, kutilgan 2 ta chaqiriq", events)
+	}
+	for _, event := range events {
+		if event.Type == EventIssued {
+			t.Fatalf("snapshot da issued hodisasi: %+v", event)
+		}
+	}
+}
+
+func TestSnapshotIncludesOutcome(t *testing.T) {
+	hub := NewHub(2)
+	hub.PublishCall(call("t1", "A001"))
+	skipped := call("t1", "A001")
+	skipped.Type = EventSkipped
+	hub.Publish(skipped)
+
+	events := hub.Snapshot("204", 0)["204"]
+	if len(events) != 2 || events[0].Type != EventSkipped || events[1].Type != EventCalled {
+		t.Fatalf("snapshot = %+v", events)
+	}
+
+	// Chaqiriq tarixdan chiqqanda uning yakuni ham o'chadi
+	hub.PublishCall(call("t2", "A002"))
+	hub.PublishCall(call("t3", "A003"))
+	for _, event := range hub.Snapshot("204", 0)["204"] {
+		if event.TicketID == "t1" {
+			t.Fatalf("eski chipta snapshot da qoldi: %+v", event)
+		}
+	}
+}
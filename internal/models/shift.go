@@ -0,0 +1,115 @@
+// ============================================
+// SMENA VA NAVBAT MODELLARI - KASALXONA PRINTER TIZIMI
+// Smenalar, chiptalar va smena hisobotining data strukturalari
+// ============================================
+
+package models
+
+import (
+	"time"
+)
+
+// ==============================
+// SMENA HOLATLARI
+// ==============================
+
+const (
+	ShiftStatusOpen   = "open"
+	ShiftStatusClosed = "closed"
+)
+
+// ==============================
+// SMENA STRUKTURASI
+// ==============================
+
+// Shift - bo'limning bitta ish smenasi (masalan: ertalabki yoki tushdan keyingi)
+// Har bir smenada navbat raqamlari 1 dan boshlanadi
+type Shift struct {
+	// ID - smenaning unikal identifikatori
+	ID string `json:"id"`
+
+	// DepartmentName - smena tegishli bo'lgan bo'lim nomi
+	DepartmentName string `json:"department_name"`
+
+	// Prefix - navbat raqami oldidagi harf (K - Kardiologiya, T - Terapiya)
+	// Bo'sh bo'lsa, raqam harfsiz ko'rsatiladi: "015"
+	Prefix string `json:"prefix,omitempty"`
+
+	// Status - smena holati: open yoki closed
+	Status string `json:"status"`
+
+	// LastNumber - shu smenada berilgan oxirgi navbat raqami
+	LastNumber int `json:"last_number"`
+
+	OpenedAt time.Time  `json:"opened_at"`
+	ClosedAt *time.Time `json:"closed_at,omitempty"`
+}
+
+// ==============================
+// CHIPTA STRUKTURASI
+// ==============================
+
+// Ticket - smena ichida berilgan navbat chiptasi
+// Vaqt belgilari kutish va qabul vaqtini hisoblash uchun saqlanadi
+type Ticket struct {
+	// ID - chiptaning unikal identifikatori (taxmin qilib bo'lmaydigan)
+	ID string `json:"id"`
+
+	// ShiftID - chipta berilgan smena
+	ShiftID string `json:"shift_id"`
+
+	DepartmentName string `json:"department_name"`
+	DoctorID       string `json:"doctor_id,omitempty"`
+	RoomNumber     string `json:"room_number"`
+
+	// Number - smena ichidagi tartib raqami (1, 2, 3, ...)
+	Number int `json:"number"`
+
+	// QueueNumber - ekranda va chiptada ko'rsatiladigan format: "K-001"
+	QueueNumber string `json:"queue_number"`
+
+	// Status - waiting, called, in_progress, completed, cancelled, missed, unfinished
+	Status string `json:"status"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	CalledAt   *time.Time `json:"called_at,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// IsFinal - chipta yakuniy holatdami (boshqa o'zgarmaydi)
+func (t *Ticket) IsFinal() bool {
+	switch t.Status {
+	case StatusCompleted, StatusCancelled, StatusMissed, StatusUnfinished:
+		return true
+	}
+	return false
+}
+
+// ==============================
+// SMENA HISOBOTI
+// ==============================
+
+// ShiftSummary - smena yopilganda chop etiladigan hisobot
+type ShiftSummary struct {
+	ShiftID        string     `json:"shift_id"`
+	DepartmentName string     `json:"department_name"`
+	OpenedAt       time.Time  `json:"opened_at"`
+	ClosedAt       *time.Time `json:"closed_at,omitempty"`
+
+	// Chiptalar soni holatlar bo'yicha
+	Issued     int `json:"issued"`
+	Served     int `json:"served"`
+	Missed     int `json:"missed"`
+	Cancelled  int `json:"cancelled"`
+	Unfinished int `json:"unfinished"` // Smena yopilganda ochiq qolgan chiptalar
+	Waiting    int `json:"waiting"`
+
+	// AvgWait - chipta olingandan chaqirilgungacha o'rtacha vaqt
+	AvgWait time.Duration `json:"-"`
+	// AvgService - qabul boshlangandan tugagungacha o'rtacha vaqt
+	AvgService time.Duration `json:"-"`
+
+	AvgWaitSeconds    float64 `json:"avg_wait_seconds"`
+	AvgServiceSeconds float64 `json:"avg_service_seconds"`
+}
@@ -21,10 +21,11 @@ type PrintRequest struct {
 	// Misol: "c14da83e-36d1-4a28-8ce2-6bebab4a18cb"
 	TicketID string `json:"ticket_id" binding:"required"`
 
-	// // ShiftID - smena yoki ish vaqtining identifikatori
-	// // Har bir smenada navbat raqamlari 1 dan boshlanadi
-	// // Misol: "c363d327-d777-4727-83a3-1cef84747664"
-	// ShiftID string `json:"shift_id" binding:"required"`
+	// ShiftID - smena yoki ish vaqtining identifikatori
+	// Har bir smenada navbat raqamlari 1 dan boshlanadi
+	// Ixtiyoriy - tashqi tizimdan kelgan chiptalarda bo'lmasligi mumkin
+	// Misol: "c363d327-d777-4727-83a3-1cef84747664"
+	ShiftID string `json:"shift_id"`
 
 	DoctorId string `json:"doctor_id" binding:"required"`
 
@@ -214,6 +215,7 @@ const (
 	StatusCompleted  = "completed"
 	StatusCancelled  = "cancelled"
 	StatusMissed     = "missed"
+	StatusUnfinished = "unfinished" // Smena yopilganda chaqirilmagan yoki qabuli tugallanmagan
 )
 
 // Xato kodlari - bir xil formatda ishlatish uchun
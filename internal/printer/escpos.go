@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"fmt"
 	"pos80/internal/models"
+	"strings"
 	"time"
 )
 
@@ -25,7 +26,11 @@ import (
 // - Stateless: Chaqiruvlar orasida ichki holat saqlanmaydi
 // - Composable: Har bir formatlash metodi mustaqil va test qilinishi mumkin
 // - Xatolarga chidamli: Chegara holatlarini yaxshi boshqaradi
-type TicketFormatter struct{}
+type TicketFormatter struct {
+	// statusBaseURL - chipta holati sahifasining manzili (QR kod uchun)
+	// Bo'sh bo'lsa, chiptada QR kod chop etilmaydi
+	statusBaseURL string
+}
 
 // NewTicketFormatter yangi chipta formatter instance'ini yaratadi.
 // Factory pattern orqali kelajakda formatter konfiguratsiyasini o'zgartirish imkoniyati.
@@ -33,6 +38,14 @@ func NewTicketFormatter() *TicketFormatter {
 	return &TicketFormatter{}
 }
 
+// WithStatusURL chiptaga holat sahifasiga olib boruvchi QR kod qo'shadi.
+// baseURL: serverning tashqi manzili, masalan "http://192.168.100.86:8080"
+// QR kodda "<baseURL>/t/<ticket_id>" yoziladi.
+func (tf *TicketFormatter) WithStatusURL(baseURL string) *TicketFormatter {
+	tf.statusBaseURL = strings.TrimRight(baseURL, "/")
+	return tf
+}
+
 func (tf *TicketFormatter) Format(req models.PrintRequest) []byte {
 	buffer := bytes.NewBuffer(nil)
 
@@ -76,6 +89,13 @@ func (tf *TicketFormatter) Format(req models.PrintRequest) []byte {
 	buffer.WriteString(req.RoomNumber + "-xona \n")
 	buffer.WriteString(formatUzbek(time.Now()) + "\n\n")
 
+	// QR KOD - bemor telefonda navbat holatini kuzatishi uchun
+	if tf.statusBaseURL != "" && req.TicketID != "" {
+		tf.writeCentered(buffer)
+		tf.writeQRCode(buffer, tf.statusBaseURL+"/t/"+req.TicketID)
+		buffer.WriteString("\nNavbatingizni telefonda kuzating\n\n")
+	}
+
 	// PASTKI QISM
 	tf.writeCentered(buffer)
 	tf.writeBold(buffer, true)
@@ -171,6 +191,7 @@ func (tf *TicketFormatter) getStatusText(status string) string {
 		"completed":   "YAKUNLANDI",    // Qabul muvaffaqiyatli tugadi
 		"cancelled":   "BEKOR QILINDI", // Navbat bekor qilindi
 		"missed":      "KELMADI",       // Bemor chaqirilganda kelmadi
+		"unfinished":  "YAKUNLANMAGAN", // Smena yopilganda ochiq qolgan
 	}
 
 	// Agar holat mavjud bo'lsa, unga mos matn qaytariladi
@@ -225,6 +246,25 @@ func (tf *TicketFormatter) writeInvert(buffer *bytes.Buffer, enable bool) {
 	}
 }
 
+// writeQRCode QR kodni chop etadi
+// GS ( k - QR kod funksiyalari (model 2, o'lcham 6, xato tuzatish M)
+func (tf *TicketFormatter) writeQRCode(buffer *bytes.Buffer, data string) {
+	// Model 2
+	buffer.Write([]byte{0x1D, 0x28, 0x6B, 0x04, 0x00, 0x31, 0x41, 0x32, 0x00})
+	// Modul o'lchami (1-16)
+	buffer.Write([]byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x43, 0x06})
+	// Xato tuzatish darajasi: M
+	buffer.Write([]byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x45, 0x31})
+
+	// Ma'lumotni printer xotirasiga yozish: uzunlik = data + 3
+	length := len(data) + 3
+	buffer.Write([]byte{0x1D, 0x28, 0x6B, byte(length % 256), byte(length / 256), 0x31, 0x50, 0x30})
+	buffer.WriteString(data)
+
+	// Xotiradagi QR kodni chop etish
+	buffer.Write([]byte{0x1D, 0x28, 0x6B, 0x03, 0x00, 0x31, 0x51, 0x30})
+}
+
 // formatUzbek sanani o'zbek tilida formatlaydi
 func formatUzbek(t time.Time) string {
 	// O'zbek tilida oylar
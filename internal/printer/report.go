@@ -0,0 +1,100 @@
+// ============================================
+// SMENA HISOBOTI FORMATTER
+// Smena yopilganda termal printerda chop etiladigan qisqa hisobot
+// ============================================
+
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"pos80/internal/models"
+	"time"
+)
+
+// FormatShiftReport smena hisobotini ESC/POS formatiga o'giradi.
+// Chiptada: berilgan, qabul qilingan, kelmagan va bekor qilingan chiptalar soni,
+// o'rtacha kutish va qabul vaqti chop etiladi.
+func (tf *TicketFormatter) FormatShiftReport(summary models.ShiftSummary) []byte {
+	buffer := bytes.NewBuffer(nil)
+
+	// Printerni reset qilish
+	buffer.Write([]byte{0x1B, 0x40})
+
+	// SARLAVHA
+	tf.writeCentered(buffer)
+	tf.writeBold(buffer, true)
+	tf.writeSize(buffer, 2, 2)
+	buffer.WriteString("SMENA HISOBOTI\n")
+	tf.resetFormatting(buffer)
+
+	tf.writeCentered(buffer)
+	tf.writeBold(buffer, true)
+	buffer.WriteString(summary.DepartmentName + "\n")
+	tf.resetFormatting(buffer)
+
+	tf.writeCentered(buffer)
+	buffer.WriteString("========================================\n")
+
+	// SMENA VAQTI
+	tf.writeLeftAligned(buffer)
+	buffer.WriteString("Ochildi:  " + formatUzbek(summary.OpenedAt) + "\n")
+	if summary.ClosedAt != nil {
+		buffer.WriteString("Yopildi:  " + formatUzbek(*summary.ClosedAt) + "\n")
+	}
+	buffer.WriteString("----------------------------------------\n")
+
+	// CHIPTALAR SONI
+	tf.writeReportLine(buffer, "Berilgan chiptalar", fmt.Sprintf("%d", summary.Issued))
+	tf.writeReportLine(buffer, "Qabul qilindi", fmt.Sprintf("%d", summary.Served))
+	tf.writeReportLine(buffer, "Kelmadi", fmt.Sprintf("%d", summary.Missed))
+	tf.writeReportLine(buffer, "Bekor qilindi", fmt.Sprintf("%d", summary.Cancelled))
+	if summary.Unfinished > 0 {
+		tf.writeReportLine(buffer, "Yakunlanmagan", fmt.Sprintf("%d", summary.Unfinished))
+	}
+	if summary.Waiting > 0 {
+		tf.writeReportLine(buffer, "Kutmoqda", fmt.Sprintf("%d", summary.Waiting))
+	}
+	buffer.WriteString("----------------------------------------\n")
+
+	// O'RTACHA VAQTLAR
+	tf.writeReportLine(buffer, "O'rtacha kutish", formatDuration(summary.AvgWait))
+	tf.writeReportLine(buffer, "O'rtacha qabul", formatDuration(summary.AvgService))
+
+	tf.writeCentered(buffer)
+	buffer.WriteString("========================================\n")
+	buffer.WriteString("Chop etildi: " + formatUzbek(time.Now()) + "\n")
+	tf.resetFormatting(buffer)
+
+	// QOG'OZNI KESISH
+	buffer.Write([]byte("\n\n\n\n"))
+	buffer.Write([]byte{0x1D, 0x56, 0x00})
+
+	return buffer.Bytes()
+}
+
+// writeReportLine chapda nom, o'ngda qiymat bo'lgan qatorni yozadi
+// 80mm qog'ozda 42 belgili qator ishlatiladi
+func (tf *TicketFormatter) writeReportLine(buffer *bytes.Buffer, label, value string) {
+	const lineWidth = 42
+	padding := lineWidth - len(label) - len(value)
+	if padding < 1 {
+		padding = 1
+	}
+	buffer.WriteString(label)
+	buffer.Write(bytes.Repeat([]byte{' '}, padding))
+	buffer.WriteString(value + "\n")
+}
+
+// formatDuration davomiylikni o'qilishi oson ko'rinishga o'giradi
+// Misol: "12 daq 30 s", "45 s"
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	minutes := int(d / time.Minute)
+	seconds := int((d % time.Minute) / time.Second)
+
+	if minutes == 0 {
+		return fmt.Sprintf("%d s", seconds)
+	}
+	return fmt.Sprintf("%d daq %d s", minutes, seconds)
+}
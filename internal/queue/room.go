@@ -0,0 +1,124 @@
+package queue
+
+import (
+	"errors"
+	"strings"
+
+	"pos80/internal/models"
+)
+
+var (
+	ErrRoomQueueEmpty  = errors.New("xonada kutayotgan bemor yo'q")
+	ErrNoCurrentTicket = errors.New("xonada chaqirilgan bemor yo'q")
+)
+
+// RoomQueue - xonaning joriy holati (shifokor paneli uchun)
+type RoomQueue struct {
+	RoomNumber string          `json:"room_number"`
+	Current    *models.Ticket  `json:"current,omitempty"` // Chaqirilgan yoki qabuldagi bemor
+	Waiting    []models.Ticket `json:"waiting"`           // Kutayotganlar, navbat tartibida
+}
+
+// RoomQueue - xonaning ochiq smenalardagi navbatini qaytaradi
+func (s *Service) RoomQueue(room string) RoomQueue {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := RoomQueue{
+		RoomNumber: room,
+		Waiting:    []models.Ticket{},
+	}
+	for _, ticket := range s.roomTicketsLocked(room) {
+		if ticket.Status == models.StatusWaiting {
+			result.Waiting = append(result.Waiting, *ticket)
+		}
+	}
+	if current := s.currentLocked(room); current != nil {
+		ticket := *current
+		result.Current = &ticket
+	}
+	return result
+}
+
+// CallNext - xonadagi navbatdagi birinchi bemorni chaqirilgan deb belgilaydi
+// Oldingi bemor avval yakunlanadi: qabuldagisi - qabul qilindi, faqat chaqirilgani - kelmadi.
+// Yakunlangan oldingi chiptalar ham qaytariladi (ekranlarga xabar berish uchun)
+func (s *Service) CallNext(room string) (models.Ticket, []models.Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var next *models.Ticket
+	var open []*models.Ticket
+	for _, ticket := range s.roomTicketsLocked(room) {
+		switch ticket.Status {
+		case models.StatusWaiting:
+			if next == nil {
+				next = ticket
+			}
+		case models.StatusCalled, models.StatusInProgress:
+			open = append(open, ticket)
+		}
+	}
+	if next == nil {
+		return models.Ticket{}, nil, ErrRoomQueueEmpty
+	}
+
+	finished := make([]models.Ticket, 0, len(open))
+	for _, ticket := range open {
+		status := models.StatusMissed
+		if ticket.Status == models.StatusInProgress {
+			status = models.StatusCompleted
+		}
+		previous, err := s.updateStatusLocked(ticket.ID, status)
+		if err != nil {
+			return models.Ticket{}, finished, err
+		}
+		finished = append(finished, previous)
+	}
+
+	ticket, err := s.updateStatusLocked(next.ID, models.StatusCalled)
+	return ticket, finished, err
+}
+
+// CurrentTicket - xonada hozir chaqirilgan yoki qabuldagi bemor
+func (s *Service) CurrentTicket(room string) (models.Ticket, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	current := s.currentLocked(room)
+	if current == nil {
+		return models.Ticket{}, ErrNoCurrentTicket
+	}
+	return *current, nil
+}
+
+// roomTicketsLocked - xonaning ochiq smenalardagi chiptalari, berilish tartibida
+func (s *Service) roomTicketsLocked(room string) []*models.Ticket {
+	var tickets []*models.Ticket
+	for _, id := range s.order {
+		ticket := s.tickets[id]
+		if !strings.EqualFold(ticket.RoomNumber, room) {
+			continue
+		}
+		shift, ok := s.shifts[ticket.ShiftID]
+		if !ok || shift.Status != models.ShiftStatusOpen {
+			continue
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets
+}
+
+// currentLocked - eng oxirgi chaqirilgan, hali yakunlanmagan chipta
+func (s *Service) currentLocked(room string) *models.Ticket {
+	var current *models.Ticket
+	for _, ticket := range s.roomTicketsLocked(room) {
+		if ticket.Status != models.StatusCalled && ticket.Status != models.StatusInProgress {
+			continue
+		}
+		if current == nil || ticket.CalledAt.After(*current.CalledAt) {
+			current = ticket
+		}
+	}
+	return current
+}
@@ -0,0 +1,381 @@
+// ============================================
+// NAVBAT SERVISI - KASALXONA PRINTER TIZIMI
+// Bo'limlar bo'yicha smenalar, chipta berish va holatlarni boshqarish
+// ============================================
+
+package queue
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pos80/internal/models"
+)
+
+// ==============================
+// XATOLAR
+// ==============================
+
+var (
+	ErrShiftNotFound     = errors.New("smena topilmadi")
+	ErrShiftAlreadyOpen  = errors.New("bu bo'limda ochiq smena allaqachon bor")
+	ErrShiftClosed       = errors.New("smena yopilgan")
+	ErrNoOpenShift       = errors.New("bu bo'limda ochiq smena yo'q")
+	ErrTicketNotFound    = errors.New("chipta topilmadi")
+	ErrInvalidStatus     = errors.New("noto'g'ri chipta holati")
+	ErrTicketFinalized   = errors.New("chipta allaqachon yakunlangan")
+	ErrDepartmentMissing = errors.New("department_name talab qilinadi")
+)
+
+// ==============================
+// NAVBAT SERVISI
+// ==============================
+
+// Service - smenalar va chiptalarni xotirada saqlaydi
+// Har bir o'zgarishdan keyin ochiq smenalar data papkasiga yoziladi,
+// shuning uchun dastur qayta ishga tushganda navbat raqamlari yo'qolmaydi.
+// Yopilgan smena data/archive dagi kunlik faylga ko'chiriladi va
+// ClosedShiftRetention dan keyin xotiradan ham o'chiriladi
+type Service struct {
+	mu         sync.RWMutex
+	shifts     map[string]*models.Shift
+	tickets    map[string]*models.Ticket
+	order      []string        // chiptalar berilish tartibida
+	archived   map[string]bool // Arxiv fayliga yozilgan yopiq smenalar
+	storePath  string
+	archiveDir string
+}
+
+// NewService - yangi navbat servisi yaratadi va saqlangan holatni yuklaydi
+func NewService(dataDir string) *Service {
+	s := &Service{
+		shifts:     make(map[string]*models.Shift),
+		tickets:    make(map[string]*models.Ticket),
+		archived:   make(map[string]bool),
+		storePath:  storeFile(dataDir),
+		archiveDir: archiveDir(dataDir),
+	}
+
+	if err := s.load(); err != nil {
+		log.Printf("⚠️ Navbat holatini yuklab bo'lmadi: %v", err)
+	} else {
+		log.Printf("✅ Navbat holati yuklandi: %d smena, %d chipta", len(s.shifts), len(s.tickets))
+	}
+
+	return s
+}
+
+// ==============================
+// SMENA OPERATSIYALARI
+// ==============================
+
+// OpenShift - bo'lim uchun yangi smena ochadi
+// Bo'limda ochiq smena bo'lsa, ErrShiftAlreadyOpen qaytariladi
+func (s *Service) OpenShift(department, prefix string) (models.Shift, error) {
+	department = strings.TrimSpace(department)
+	if department == "" {
+		return models.Shift{}, ErrDepartmentMissing
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.openShiftLocked(department); ok {
+		return models.Shift{}, ErrShiftAlreadyOpen
+	}
+
+	shift := &models.Shift{
+		ID:             newID(),
+		DepartmentName: department,
+		Prefix:         strings.ToUpper(strings.TrimSpace(prefix)),
+		Status:         models.ShiftStatusOpen,
+		OpenedAt:       time.Now(),
+	}
+	s.shifts[shift.ID] = shift
+	s.pruneLocked(shift.OpenedAt)
+	s.saveLocked()
+
+	log.Printf("🟢 Smena ochildi: %s (%s)", shift.DepartmentName, shift.ID)
+	return *shift, nil
+}
+
+// CloseShift - smenani yopadi va hisobotni qaytaradi
+// Ochiq qolgan chiptalar (kutayotgan, chaqirilgan, qabuldagi) yakunlanmagan deb belgilanadi,
+// bekor qilinganlar soniga qo'shilmaydi
+func (s *Service) CloseShift(shiftID string) (models.ShiftSummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shift, ok := s.shifts[shiftID]
+	if !ok {
+		return models.ShiftSummary{}, ErrShiftNotFound
+	}
+	if shift.Status == models.ShiftStatusClosed {
+		return models.ShiftSummary{}, ErrShiftClosed
+	}
+
+	now := time.Now()
+	for _, id := range s.order {
+		ticket := s.tickets[id]
+		if ticket.ShiftID != shiftID || ticket.IsFinal() {
+			continue
+		}
+		ticket.Status = models.StatusUnfinished
+		ticket.FinishedAt = &now
+	}
+
+	shift.Status = models.ShiftStatusClosed
+	shift.ClosedAt = &now
+	summary := s.summaryLocked(shift)
+
+	// Arxivga yozilmasa, smena queue.json da qoladi va keyingi ishga tushishda qayta uriniladi
+	if err := s.archiveLocked(shift); err != nil {
+		log.Printf("⚠️ Smena arxivlanmadi: %v", err)
+	}
+	s.pruneLocked(now)
+	s.saveLocked()
+
+	log.Printf("🔴 Smena yopildi: %s (%s)", shift.DepartmentName, shift.ID)
+	return summary, nil
+}
+
+// CurrentShift - bo'limning ochiq smenasini qaytaradi
+func (s *Service) CurrentShift(department string) (models.Shift, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	shift, ok := s.openShiftLocked(department)
+	if !ok {
+		return models.Shift{}, false
+	}
+	return *shift, true
+}
+
+// GetShift - smenani ID bo'yicha qaytaradi
+func (s *Service) GetShift(shiftID string) (models.Shift, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	shift, ok := s.shifts[shiftID]
+	if !ok {
+		return models.Shift{}, ErrShiftNotFound
+	}
+	return *shift, nil
+}
+
+// ListShifts - xotiradagi smenalar (ochiq va yaqinda yopilganlar), eng yangisi birinchi
+// Eskiroq smenalar data/archive dagi kunlik fayllarda
+func (s *Service) ListShifts() []models.Shift {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	shifts := make([]models.Shift, 0, len(s.shifts))
+	for _, shift := range s.shifts {
+		shifts = append(shifts, *shift)
+	}
+	sort.Slice(shifts, func(i, j int) bool {
+		return shifts[i].OpenedAt.After(shifts[j].OpenedAt)
+	})
+	return shifts
+}
+
+// Summary - smena hisobotini hisoblaydi (ochiq smena uchun ham)
+func (s *Service) Summary(shiftID string) (models.ShiftSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	shift, ok := s.shifts[shiftID]
+	if !ok {
+		return models.ShiftSummary{}, ErrShiftNotFound
+	}
+	return s.summaryLocked(shift), nil
+}
+
+// ==============================
+// CHIPTA OPERATSIYALARI
+// ==============================
+
+// IssueTicket - bo'limning ochiq smenasida yangi chipta beradi
+// Navbat raqami smena hisoblagichidan olinadi
+func (s *Service) IssueTicket(department, doctorID, room string) (models.Ticket, error) {
+	department = strings.TrimSpace(department)
+	if department == "" {
+		return models.Ticket{}, ErrDepartmentMissing
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shift, ok := s.openShiftLocked(department)
+	if !ok {
+		return models.Ticket{}, ErrNoOpenShift
+	}
+
+	shift.LastNumber++
+	ticket := &models.Ticket{
+		ID:             newID(),
+		ShiftID:        shift.ID,
+		DepartmentName: shift.DepartmentName,
+		DoctorID:       doctorID,
+		RoomNumber:     room,
+		Number:         shift.LastNumber,
+		QueueNumber:    formatQueueNumber(shift.Prefix, shift.LastNumber),
+		Status:         models.StatusWaiting,
+		CreatedAt:      time.Now(),
+	}
+	s.tickets[ticket.ID] = ticket
+	s.order = append(s.order, ticket.ID)
+	s.saveLocked()
+
+	log.Printf("🎫 Chipta berildi: %s (%s, xona %s)", ticket.QueueNumber, ticket.DepartmentName, ticket.RoomNumber)
+	return *ticket, nil
+}
+
+// GetTicket - chiptani ID bo'yicha qaytaradi
+func (s *Service) GetTicket(ticketID string) (models.Ticket, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ticket, ok := s.tickets[ticketID]
+	if !ok {
+		return models.Ticket{}, ErrTicketNotFound
+	}
+	return *ticket, nil
+}
+
+// UpdateStatus - chipta holatini o'zgartiradi va vaqt belgisini qo'yadi
+// called -> CalledAt, in_progress -> StartedAt, yakuniy holatlar -> FinishedAt
+func (s *Service) UpdateStatus(ticketID, status string) (models.Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.updateStatusLocked(ticketID, status)
+}
+
+// ==============================
+// ICHKI YORDAMCHI METODLAR
+// ==============================
+
+// updateStatusLocked - UpdateStatus ning asosiy qismi (mu ushlangan bo'lishi kerak)
+func (s *Service) updateStatusLocked(ticketID, status string) (models.Ticket, error) {
+	ticket, ok := s.tickets[ticketID]
+	if !ok {
+		return models.Ticket{}, ErrTicketNotFound
+	}
+	if ticket.IsFinal() {
+		return models.Ticket{}, ErrTicketFinalized
+	}
+
+	now := time.Now()
+	switch status {
+	case models.StatusWaiting:
+	case models.StatusCalled:
+		if ticket.CalledAt == nil {
+			ticket.CalledAt = &now
+		}
+	case models.StatusInProgress:
+		if ticket.CalledAt == nil {
+			ticket.CalledAt = &now
+		}
+		ticket.StartedAt = &now
+	case models.StatusCompleted, models.StatusCancelled, models.StatusMissed:
+		ticket.FinishedAt = &now
+	default:
+		return models.Ticket{}, fmt.Errorf("%w: %s", ErrInvalidStatus, status)
+	}
+
+	ticket.Status = status
+	s.saveLocked()
+
+	log.Printf("🔄 Chipta holati: %s -> %s", ticket.QueueNumber, status)
+	return *ticket, nil
+}
+
+// openShiftLocked - bo'limning ochiq smenasini topadi (mu ushlangan bo'lishi kerak)
+func (s *Service) openShiftLocked(department string) (*models.Shift, bool) {
+	for _, shift := range s.shifts {
+		if shift.Status == models.ShiftStatusOpen && strings.EqualFold(shift.DepartmentName, department) {
+			return shift, true
+		}
+	}
+	return nil, false
+}
+
+// summaryLocked - smena chiptalari bo'yicha statistikani hisoblaydi
+func (s *Service) summaryLocked(shift *models.Shift) models.ShiftSummary {
+	summary := models.ShiftSummary{
+		ShiftID:        shift.ID,
+		DepartmentName: shift.DepartmentName,
+		OpenedAt:       shift.OpenedAt,
+		ClosedAt:       shift.ClosedAt,
+	}
+
+	var waitTotal, serviceTotal time.Duration
+	var waitCount, serviceCount int
+
+	for _, id := range s.order {
+		ticket := s.tickets[id]
+		if ticket.ShiftID != shift.ID {
+			continue
+		}
+
+		summary.Issued++
+		switch ticket.Status {
+		case models.StatusCompleted:
+			summary.Served++
+		case models.StatusMissed:
+			summary.Missed++
+		case models.StatusCancelled:
+			summary.Cancelled++
+		case models.StatusUnfinished:
+			summary.Unfinished++
+		default:
+			summary.Waiting++
+		}
+
+		if ticket.CalledAt != nil {
+			waitTotal += ticket.CalledAt.Sub(ticket.CreatedAt)
+			waitCount++
+		}
+		if ticket.Status == models.StatusCompleted && ticket.StartedAt != nil && ticket.FinishedAt != nil {
+			serviceTotal += ticket.FinishedAt.Sub(*ticket.StartedAt)
+			serviceCount++
+		}
+	}
+
+	if waitCount > 0 {
+		summary.AvgWait = waitTotal / time.Duration(waitCount)
+	}
+	if serviceCount > 0 {
+		summary.AvgService = serviceTotal / time.Duration(serviceCount)
+	}
+	summary.AvgWaitSeconds = summary.AvgWait.Seconds()
+	summary.AvgServiceSeconds = summary.AvgService.Seconds()
+
+	return summary
+}
+
+// formatQueueNumber - "K-015" yoki prefiks bo'lmasa "015"
+func formatQueueNumber(prefix string, number int) string {
+	if prefix == "" {
+		return fmt.Sprintf("%03d", number)
+	}
+	return fmt.Sprintf("%s-%03d", prefix, number)
+}
+
+// newID - taxmin qilib bo'lmaydigan tasodifiy identifikator
+// Chipta ID si ommaviy havolalarda ishlatiladi, shuning uchun ketma-ket bo'lmasligi kerak
+func newID() string {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
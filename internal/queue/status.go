@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"strings"
+	"time"
+
+	"pos80/internal/models"
+)
+
+// recentServiceSamples - taxminiy kutish vaqti uchun olinadigan oxirgi qabullar soni
+const recentServiceSamples = 10
+
+// TicketStatus - bemor o'z chiptasi bo'yicha ko'radigan ommaviy ma'lumot
+// Boshqa bemorlar haqida faqat oldindagilar soni ko'rsatiladi
+type TicketStatus struct {
+	QueueNumber    string     `json:"queue_number"`
+	Status         string     `json:"status"`
+	RoomNumber     string     `json:"room_number"`
+	DepartmentName string     `json:"department_name"`
+	Ahead          int        `json:"ahead"`                            // Oldingizda nechta bemor bor
+	EstimatedWait  *float64   `json:"estimated_wait_seconds,omitempty"` // Ma'lumot yetarli bo'lmasa bo'sh
+	CreatedAt      time.Time  `json:"created_at"`
+	CalledAt       *time.Time `json:"called_at,omitempty"`
+}
+
+// TicketStatus - chiptaning holati, xona navbatidagi o'rni va taxminiy kutish vaqti
+// Taxmin shu xonadagi oxirgi qabullarning o'rtacha davomiyligidan hisoblanadi
+func (s *Service) TicketStatus(ticketID string) (TicketStatus, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ticket, ok := s.tickets[ticketID]
+	if !ok {
+		return TicketStatus{}, ErrTicketNotFound
+	}
+
+	status := TicketStatus{
+		QueueNumber:    ticket.QueueNumber,
+		Status:         ticket.Status,
+		RoomNumber:     ticket.RoomNumber,
+		DepartmentName: ticket.DepartmentName,
+		CreatedAt:      ticket.CreatedAt,
+		CalledAt:       ticket.CalledAt,
+	}
+
+	if ticket.Status != models.StatusWaiting {
+		return status, nil
+	}
+
+	// Oldindagilar: shu xonada, ochiq smenada, hali chaqirilmagan va oldinroq olingan chiptalar
+	roomTickets := s.roomTicketsLocked(ticket.RoomNumber)
+	busy := false
+	for _, other := range roomTickets {
+		if other.ID == ticket.ID {
+			break
+		}
+		if other.Status == models.StatusWaiting {
+			status.Ahead++
+		}
+	}
+	for _, other := range roomTickets {
+		if other.Status == models.StatusCalled || other.Status == models.StatusInProgress {
+			busy = true
+			break
+		}
+	}
+
+	if avg, ok := s.averageServiceLocked(ticket.RoomNumber); ok {
+		// Xonada hozir qabul davom etayotgan bo'lsa, u ham kutish vaqtiga qo'shiladi
+		slots := status.Ahead
+		if busy {
+			slots++
+		}
+		wait := (avg * time.Duration(slots)).Seconds()
+		status.EstimatedWait = &wait
+	}
+
+	return status, nil
+}
+
+// averageServiceLocked - xonadagi oxirgi qabullarning o'rtacha davomiyligi
+func (s *Service) averageServiceLocked(room string) (time.Duration, bool) {
+	var total time.Duration
+	count := 0
+
+	for i := len(s.order) - 1; i >= 0 && count < recentServiceSamples; i-- {
+		ticket := s.tickets[s.order[i]]
+		if !strings.EqualFold(ticket.RoomNumber, room) || ticket.Status != models.StatusCompleted {
+			continue
+		}
+		if ticket.StartedAt == nil || ticket.FinishedAt == nil {
+			continue
+		}
+		total += ticket.FinishedAt.Sub(*ticket.StartedAt)
+		count++
+	}
+
+	if count == 0 {
+		return 0, false
+	}
+	return total / time.Duration(count), true
+}
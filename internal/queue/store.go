@@ -0,0 +1,210 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"pos80/internal/models"
+)
+
+// ClosedShiftRetention - yopilgan smena va uning chiptalari xotirada qancha turadi
+// (hisobot, /t/:ticket_id sahifasi uchun); keyin faqat arxiv faylida qoladi
+const ClosedShiftRetention = 24 * time.Hour
+
+// storeState - diskka yoziladigan navbat holati
+type storeState struct {
+	Shifts  []*models.Shift  `json:"shifts"`
+	Tickets []*models.Ticket `json:"tickets"`
+}
+
+// storeFile - holat fayli yo'li (faqat ochiq va hali arxivlanmagan smenalar)
+func storeFile(dataDir string) string {
+	return filepath.Join(dataDir, "queue.json")
+}
+
+// archiveDir - yopilgan smenalar kunlik fayllarda: archive/2024-05-14.json
+func archiveDir(dataDir string) string {
+	return filepath.Join(dataDir, "archive")
+}
+
+// load - saqlangan holatni fayldan o'qiydi
+// Fayl bo'lmasa, bo'sh holat bilan ishlanadi
+// Faylda yopilgan smenalar qolgan bo'lsa (eski format yoki arxivga yozilmay qolgan),
+// ular arxivga ko'chiriladi
+func (s *Service) load() error {
+	state, err := readState(s.storePath)
+	if err != nil {
+		return err
+	}
+
+	for _, shift := range state.Shifts {
+		s.shifts[shift.ID] = shift
+	}
+
+	sort.SliceStable(state.Tickets, func(i, j int) bool {
+		return state.Tickets[i].CreatedAt.Before(state.Tickets[j].CreatedAt)
+	})
+	for _, ticket := range state.Tickets {
+		s.tickets[ticket.ID] = ticket
+		s.order = append(s.order, ticket.ID)
+	}
+
+	archived := 0
+	for _, shift := range s.shifts {
+		if shift.Status != models.ShiftStatusClosed {
+			continue
+		}
+		if err := s.archiveLocked(shift); err != nil {
+			log.Printf("⚠️ Smena arxivlanmadi: %v", err)
+			continue
+		}
+		archived++
+	}
+	if archived > 0 {
+		s.pruneLocked(time.Now())
+		s.saveLocked()
+		log.Printf("🗄️ %d ta yopilgan smena arxivga ko'chirildi", archived)
+	}
+
+	return nil
+}
+
+// saveLocked - ochiq (va arxivga hali yozilmagan) smenalarni faylga yozadi (mu ushlangan bo'lishi kerak)
+// Yopilgan smenalar arxivda, shuning uchun fayl hajmi bitta kunlik navbatdan oshmaydi
+func (s *Service) saveLocked() {
+	state := storeState{
+		Shifts:  []*models.Shift{},
+		Tickets: []*models.Ticket{},
+	}
+	for _, shift := range s.shifts {
+		if s.persistedLocked(shift) {
+			state.Shifts = append(state.Shifts, shift)
+		}
+	}
+	for _, id := range s.order {
+		ticket := s.tickets[id]
+		if shift, ok := s.shifts[ticket.ShiftID]; ok && s.persistedLocked(shift) {
+			state.Tickets = append(state.Tickets, ticket)
+		}
+	}
+
+	if err := writeState(s.storePath, state); err != nil {
+		log.Printf("❌ Navbat holatini saqlab bo'lmadi: %v", err)
+	}
+}
+
+// persistedLocked - smena queue.json da saqlanadimi
+func (s *Service) persistedLocked(shift *models.Shift) bool {
+	return shift.Status == models.ShiftStatusOpen || !s.archived[shift.ID]
+}
+
+// archiveLocked - yopilgan smenani va uning chiptalarini yopilgan kun fayliga qo'shadi
+// Smena faylda bo'lsa, almashtiriladi (qayta urinish ikki nusxa yaratmaydi)
+func (s *Service) archiveLocked(shift *models.Shift) error {
+	closedAt := shift.OpenedAt
+	if shift.ClosedAt != nil {
+		closedAt = *shift.ClosedAt
+	}
+	path := filepath.Join(s.archiveDir, closedAt.Format("2006-01-02")+".json")
+
+	state, err := readState(path)
+	if err != nil {
+		return err
+	}
+
+	archive := storeState{Shifts: []*models.Shift{}, Tickets: []*models.Ticket{}}
+	for _, archivedShift := range state.Shifts {
+		if archivedShift.ID != shift.ID {
+			archive.Shifts = append(archive.Shifts, archivedShift)
+		}
+	}
+	for _, ticket := range state.Tickets {
+		if ticket.ShiftID != shift.ID {
+			archive.Tickets = append(archive.Tickets, ticket)
+		}
+	}
+	archive.Shifts = append(archive.Shifts, shift)
+	for _, id := range s.order {
+		if ticket := s.tickets[id]; ticket.ShiftID == shift.ID {
+			archive.Tickets = append(archive.Tickets, ticket)
+		}
+	}
+
+	if err := writeState(path, archive); err != nil {
+		return err
+	}
+	s.archived[shift.ID] = true
+	return nil
+}
+
+// pruneLocked - ClosedShiftRetention dan oldin yopilgan va arxivlangan smenalarni xotiradan o'chiradi
+func (s *Service) pruneLocked(now time.Time) {
+	removed := make(map[string]bool)
+	for id, shift := range s.shifts {
+		if shift.Status != models.ShiftStatusClosed || !s.archived[id] || shift.ClosedAt == nil {
+			continue
+		}
+		if now.Sub(*shift.ClosedAt) < ClosedShiftRetention {
+			continue
+		}
+		removed[id] = true
+		delete(s.shifts, id)
+		delete(s.archived, id)
+	}
+	if len(removed) == 0 {
+		return
+	}
+
+	order := s.order[:0]
+	for _, id := range s.order {
+		if removed[s.tickets[id].ShiftID] {
+			delete(s.tickets, id)
+			continue
+		}
+		order = append(order, id)
+	}
+	s.order = order
+}
+
+// readState - holat faylini o'qiydi; fayl bo'lmasa bo'sh holat
+func readState(path string) (storeState, error) {
+	var state storeState
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("holat faylini o'qib bo'lmadi: %w", err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("holat fayli buzilgan (%s): %w", path, err)
+	}
+	return state, nil
+}
+
+// writeState - holatni vaqtinchalik faylga yozadi, keyin almashtiradi - yarim yozilgan fayl qolmaydi
+func writeState(path string, state storeState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serializatsiya qilib bo'lmadi: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("papkani yaratib bo'lmadi: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("yozib bo'lmadi: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("almashtirib bo'lmadi: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package queue
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pos80/internal/models"
+)
+
+func TestCloseShiftArchivesAndKeepsOnlyOpenShifts(t *testing.T) {
+	dir := t.TempDir()
+	service := NewService(dir)
+
+	closed, err := service.OpenShift("Terapiya", "T")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := service.IssueTicket("Terapiya", "", "204"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := service.CloseShift(closed.ID); err != nil {
+		t.Fatal(err)
+	}
+	open, err := service.OpenShift("Terapiya", "T")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.IssueTicket("Terapiya", "", "204"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Yopilgan smena hisobot uchun xotirada qoladi
+	if _, err := service.Summary(closed.ID); err != nil {
+		t.Fatalf("yopilgan smena hisoboti: %v", err)
+	}
+
+	state, err := readState(storeFile(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Shifts) != 1 || state.Shifts[0].ID != open.ID || len(state.Tickets) != 1 {
+		t.Fatalf("queue.json da faqat ochiq smena bo'lishi kerak: %d smena, %d chipta", len(state.Shifts), len(state.Tickets))
+	}
+
+	archive, err := readState(filepath.Join(archiveDir(dir), time.Now().Format("2006-01-02")+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archive.Shifts) != 1 || archive.Shifts[0].ID != closed.ID || len(archive.Tickets) != 3 {
+		t.Fatalf("arxivda %d smena, %d chipta; kutilgan 1 va 3", len(archive.Shifts), len(archive.Tickets))
+	}
+}
+
+func TestLoadArchivesOldClosedShifts(t *testing.T) {
+	dir := t.TempDir()
+	closedAt := time.Now().Add(-3 * 24 * time.Hour)
+	shift := &models.Shift{ID: "old", DepartmentName: "Terapiya", Status: models.ShiftStatusClosed, OpenedAt: closedAt.Add(-8 * time.Hour), ClosedAt: &closedAt}
+	ticket := &models.Ticket{ID: "t1", ShiftID: "old", QueueNumber: "001", Status: models.StatusCompleted, CreatedAt: shift.OpenedAt}
+	if err := writeState(storeFile(dir), storeState{Shifts: []*models.Shift{shift}, Tickets: []*models.Ticket{ticket}}); err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewService(dir)
+	if shifts := service.ListShifts(); len(shifts) != 0 {
+		t.Fatalf("eski smena xotirada qoldi: %+v", shifts)
+	}
+	if _, err := service.GetTicket("t1"); err == nil {
+		t.Fatal("eski chipta xotirada qoldi")
+	}
+
+	state, err := readState(storeFile(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Shifts) != 0 || len(state.Tickets) != 0 {
+		t.Fatalf("queue.json tozalanmadi: %d smena, %d chipta", len(state.Shifts), len(state.Tickets))
+	}
+	if _, err := os.Stat(filepath.Join(archiveDir(dir), closedAt.Format("2006-01-02")+".json")); err != nil {
+		t.Fatalf("arxiv fayli yo'q: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+// ============================================
+// WEB SAHIFALAR - SERVER ICHIGA JOYLANGAN HTML FAYLLAR
+// Kiosk, zal ekrani va boshqa sahifalar binary ichida embed.FS orqali tarqatiladi
+// ============================================
+
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"log"
+	"os"
+)
+
+//go:embed static
+var files embed.FS
+
+// Static - "static" papkasi ildiz sifatida ko'rinadigan fayl tizimi
+func Static() fs.FS {
+	sub, err := fs.Sub(files, "static")
+	if err != nil {
+		// embed papkasi har doim mavjud, bu holat faqat kompilyatsiya xatosida bo'ladi
+		panic(err)
+	}
+	return sub
+}
+
+// Files - sahifalar uchun fayl tizimi
+// overrideDir berilgan bo'lsa, fayl avval shu papkadan qidiriladi,
+// topilmasa binary ichidagi nusxa qaytariladi.
+// Shu tarzda kiosk dizaynini qayta kompilyatsiyasiz o'zgartirish mumkin.
+func Files(overrideDir string) fs.FS {
+	embedded := Static()
+	if overrideDir == "" {
+		return embedded
+	}
+
+	if info, err := os.Stat(overrideDir); err != nil || !info.IsDir() {
+		log.Printf("⚠️ Web papkasi topilmadi (%s), ichki sahifalar ishlatiladi", overrideDir)
+		return embedded
+	}
+
+	log.Printf("✅ Web sahifalar diskdan almashtiriladi: %s", overrideDir)
+	return overlayFS{disk: os.DirFS(overrideDir), embedded: embedded}
+}
+
+// overlayFS - diskdagi fayllar ichki fayllardan ustun turadi
+type overlayFS struct {
+	disk     fs.FS
+	embedded fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if f, err := o.disk.Open(name); err == nil {
+		return f, nil
+	}
+	return o.embedded.Open(name)
+}